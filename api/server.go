@@ -0,0 +1,299 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/application"
+	"github.com/ynori7/tvshows/premieres"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+const (
+	dateFormat     = "2006-01-02"
+	headingFormat  = "January 2" //the dates on the premieres list
+	defaultDays    = 7
+	errRunning     = "a run is in progress"
+	errInvalidDate = "invalid %s date, expected yyyy-mm-dd"
+)
+
+// PremieresSource fetches the premieres list, e.g. the premieres.PremieresClient
+type PremieresSource interface {
+	GetPotentiallyInterestingPremieres(lastProcessedDate string) (*premieres.PremiereList, error)
+}
+
+// ShowSource looks up the shows, e.g. the tvshow.ImdbClient
+type ShowSource interface {
+	SearchForTvSeriesTitle(searchTitle string) (string, error)
+	GetTvShowData(link string) (*tvshow.TvShow, error)
+	BuildTitleLink(imdbId string) string
+}
+
+// NewPremieresSource creates the premieres source for a request. The premieres client guesses the years of the
+// dates from the current date, so a new one is needed for each request.
+type NewPremieresSource func() PremieresSource
+
+// Trigger does a run of the report and returns its summary
+type Trigger func() application.Summary
+
+// Server is the JSON API over the premieres pipeline
+type Server struct {
+	premieres NewPremieresSource
+	shows     ShowSource
+	trigger   Trigger
+	token     string //needed to start runs, which are refused without one
+	logger    log.FieldLogger
+	now       func() time.Time
+
+	mu      sync.Mutex
+	running bool
+	last    *application.Summary
+}
+
+type premiereList struct {
+	StartDate string     `json:"start_date"`
+	EndDate   string     `json:"end_date"`
+	Premieres []premiere `json:"premieres"`
+	Skipped   []premiere `json:"skipped"` //not in one of the main genres
+}
+
+type premiere struct {
+	Title            string              `json:"title"`
+	IsNew            bool                `json:"is_new"`
+	Date             string              `json:"date,omitempty"` //yyyy-mm-dd
+	Network          string              `json:"network,omitempty"`
+	Season           int                 `json:"season,omitempty"`
+	Genres           []string            `json:"genres"`
+	StreamingOptions []view.JsonProvider `json:"streaming_options"`
+}
+
+type searchResult struct {
+	Title  string `json:"title"`
+	ImdbId string `json:"imdb_id"`
+	Link   string `json:"link"`
+}
+
+type reportsStatus struct {
+	Running bool                 `json:"running"`
+	Last    *application.Summary `json:"last,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func NewServer(premieres NewPremieresSource, shows ShowSource, trigger Trigger, token string, logger log.FieldLogger) *Server {
+	return &Server{
+		premieres: premieres,
+		shows:     shows,
+		trigger:   trigger,
+		token:     token,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /premieres", s.getPremieres)
+	mux.HandleFunc("GET /shows/search", s.searchShow)
+	mux.HandleFunc("GET /shows/{imdbId}", s.getShow)
+	mux.HandleFunc("GET /reports", s.getReports)
+	mux.HandleFunc("POST /reports", s.postReport)
+	return mux
+}
+
+// getPremieres returns the premieres between from and to (yyyy-mm-dd, inclusive), the last week by default
+func (s *Server) getPremieres(w http.ResponseWriter, req *http.Request) {
+	to := s.now()
+	if v := req.URL.Query().Get("to"); v != "" {
+		var err error
+		if to, err = time.Parse(dateFormat, v); err != nil {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf(errInvalidDate, "to"))
+			return
+		}
+	}
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	from := to.AddDate(0, 0, 1-defaultDays) //including to
+	if v := req.URL.Query().Get("from"); v != "" {
+		var err error
+		if from, err = time.Parse(dateFormat, v); err != nil {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf(errInvalidDate, "from"))
+			return
+		}
+	}
+	if from.After(to) {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("from is after to"))
+		return
+	}
+
+	//The list is read from the newest date until the one before from
+	list, err := s.premieres().GetPotentiallyInterestingPremieres(from.AddDate(0, 0, -1).Format(headingFormat))
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	s.writeJson(w, http.StatusOK, premiereList{
+		StartDate: from.Format(dateFormat),
+		EndDate:   to.Format(dateFormat),
+		Premieres: toPremieres(list.Premieres, from, to),
+		Skipped:   toPremieres(list.Skipped, from, to),
+	})
+}
+
+// toPremieres keeps the premieres between from and to, or without a date, sorted by the date and title
+func toPremieres(list []premieres.Premiere, from time.Time, to time.Time) []premiere {
+	filtered := make([]premieres.Premiere, 0, len(list))
+	for _, p := range list {
+		if p.Date.IsZero() || (!p.Date.Before(from) && !p.Date.After(to)) {
+			filtered = append(filtered, p)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		if !filtered[i].Date.Equal(filtered[j].Date) {
+			return filtered[i].Date.Before(filtered[j].Date)
+		}
+		return filtered[i].Title < filtered[j].Title
+	})
+
+	out := make([]premiere, 0, len(filtered))
+	for _, p := range filtered {
+		genres := make([]string, 0, len(p.Genres))
+		for _, g := range p.Genres {
+			genres = append(genres, strings.TrimSpace(g))
+		}
+		item := premiere{
+			Title:            p.Title,
+			IsNew:            p.IsNew,
+			Network:          p.Network,
+			Season:           p.Season,
+			Genres:           genres,
+			StreamingOptions: view.ToJsonProviders(p.StreamingOptions),
+		}
+		if !p.Date.IsZero() {
+			item.Date = p.Date.Format(dateFormat)
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// searchShow finds the IMDB page of the tv series with the title
+func (s *Server) searchShow(w http.ResponseWriter, req *http.Request) {
+	title := strings.TrimSpace(req.URL.Query().Get("title"))
+	if title == "" {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("missing title"))
+		return
+	}
+
+	link, err := s.shows.SearchForTvSeriesTitle(title)
+	if errors.Is(err, tvshow.ErrNoResult) {
+		s.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	s.writeJson(w, http.StatusOK, searchResult{Title: title, ImdbId: tvshow.ParseImdbId(link), Link: link})
+}
+
+// getShow looks up the details and score of the show with the IMDB ID
+func (s *Server) getShow(w http.ResponseWriter, req *http.Request) {
+	imdbId := req.PathValue("imdbId")
+	if tvshow.ParseImdbId(imdbId) != imdbId {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid IMDB ID: %s", imdbId))
+		return
+	}
+
+	show, err := s.shows.GetTvShowData(s.shows.BuildTitleLink(imdbId))
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	s.writeJson(w, http.StatusOK, view.ToJsonTvShows([]tvshow.TvShow{*show})[0])
+}
+
+// getReports tells whether a run is in progress and how the last one ended
+func (s *Server) getReports(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	status := reportsStatus{Running: s.running, Last: s.last}
+	s.mu.Unlock()
+
+	s.writeJson(w, http.StatusOK, status)
+}
+
+// postReport starts a run. With wait=true it responds with the summary once the run is done, otherwise
+// right away. It needs the token as a bearer token.
+func (s *Server) postReport(w http.ResponseWriter, req *http.Request) {
+	if s.token == "" {
+		s.writeError(w, http.StatusForbidden, fmt.Errorf("starting runs needs api.token in the config"))
+		return
+	}
+	if !s.authorized(req) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		s.writeError(w, http.StatusUnauthorized, fmt.Errorf("invalid or missing token"))
+		return
+	}
+
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		s.writeError(w, http.StatusConflict, fmt.Errorf(errRunning))
+		return
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	if req.URL.Query().Get("wait") == "true" {
+		summary := s.run()
+		s.writeJson(w, http.StatusOK, summary)
+		return
+	}
+
+	go s.run()
+	s.writeJson(w, http.StatusAccepted, reportsStatus{Running: true})
+}
+
+func (s *Server) authorized(req *http.Request) bool {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) run() application.Summary {
+	summary := s.trigger()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+	s.last = &summary
+	return summary
+}
+
+func (s *Server) writeJson(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.logger.WithFields(log.Fields{"Logger": "writeJson", "error": err}).Warn("Error writing response")
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	if status >= 500 {
+		s.logger.WithFields(log.Fields{"Logger": "Server", "error": err}).Error("Error handling request")
+	}
+	s.writeJson(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,270 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/application"
+	"github.com/ynori7/tvshows/premieres"
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+type fakePremieres struct {
+	lastProcessedDate string
+}
+
+func (f *fakePremieres) GetPotentiallyInterestingPremieres(lastProcessedDate string) (*premieres.PremiereList, error) {
+	f.lastProcessedDate = lastProcessedDate
+	return &premieres.PremiereList{
+		StartDate: lastProcessedDate,
+		EndDate:   "June 15",
+		Premieres: []premieres.Premiere{
+			{Title: "Sweet Magnolias", Date: time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC), Network: "Netflix", Season: 5, Genres: []string{"Drama "}, StreamingOptions: []streamer.Provider{{Name: streamer.Netflix, Type: streamer.Streaming}}},
+			{Title: "#BlackAF", IsNew: true, Date: time.Date(2020, 6, 8, 0, 0, 0, 0, time.UTC), Genres: []string{"Comedy"}},
+			{Title: "Too new", Date: time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC)},
+		},
+		Skipped: []premieres.Premiere{
+			{Title: "Mark vs. The Mountain", Date: time.Date(2020, 6, 9, 0, 0, 0, 0, time.UTC), Genres: []string{"Reality"}},
+		},
+	}, nil
+}
+
+func newFakePremieres() PremieresSource {
+	return &fakePremieres{}
+}
+
+type fakeShows struct{}
+
+func (fakeShows) SearchForTvSeriesTitle(title string) (string, error) {
+	switch title {
+	case "Game of Thrones":
+		return "https://www.imdb.com/title/tt0944947/", nil
+	case "Broken":
+		return "", fmt.Errorf("status code error: 503")
+	}
+	return "", tvshow.ErrNoResult
+}
+
+func (fakeShows) GetTvShowData(link string) (*tvshow.TvShow, error) {
+	if link != "https://www.imdb.com/title/tt0944947/" {
+		return nil, fmt.Errorf("status code error: 404")
+	}
+	return &tvshow.TvShow{Title: "Game of Thrones", ImdbId: "tt0944947", Link: link, Score: 100, Genres: []string{"Drama"}}, nil
+}
+
+func (fakeShows) BuildTitleLink(imdbId string) string {
+	return fmt.Sprintf("https://www.imdb.com/title/%s/", imdbId)
+}
+
+func Test_Server(t *testing.T) {
+	testcases := map[string]struct {
+		Url                       string
+		ExpectedStatus            int
+		ExpectedBody              string
+		ExpectedLastProcessedDate string
+	}{
+		"Premieres": {
+			Url:                       "/premieres?from=2020-06-08&to=2020-06-14",
+			ExpectedStatus:            http.StatusOK,
+			ExpectedLastProcessedDate: "June 7",
+			ExpectedBody: `{"start_date": "2020-06-08", "end_date": "2020-06-14",
+				"premieres": [
+					{"title": "#BlackAF", "is_new": true, "date": "2020-06-08", "genres": ["Comedy"], "streaming_options": []},
+					{"title": "Sweet Magnolias", "is_new": false, "date": "2020-06-10", "network": "Netflix", "season": 5, "genres": ["Drama"], "streaming_options": [{"name": "Netflix", "type": "streaming"}]}
+				],
+				"skipped": [
+					{"title": "Mark vs. The Mountain", "is_new": false, "date": "2020-06-09", "genres": ["Reality"], "streaming_options": []}
+				]}`,
+		},
+		"Premieres of the last week": {
+			Url:                       "/premieres",
+			ExpectedStatus:            http.StatusOK,
+			ExpectedLastProcessedDate: "June 8",
+		},
+		"Premieres with an invalid date": {
+			Url:            "/premieres?from=June+8",
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedBody:   `{"error": "invalid from date, expected yyyy-mm-dd"}`,
+		},
+		"Premieres with from after to": {
+			Url:            "/premieres?from=2020-06-15&to=2020-06-08",
+			ExpectedStatus: http.StatusBadRequest,
+		},
+		"Search": {
+			Url:            "/shows/search?title=Game+of+Thrones",
+			ExpectedStatus: http.StatusOK,
+			ExpectedBody:   `{"title": "Game of Thrones", "imdb_id": "tt0944947", "link": "https://www.imdb.com/title/tt0944947/"}`,
+		},
+		"Search without a result": {
+			Url:            "/shows/search?title=Unknown",
+			ExpectedStatus: http.StatusNotFound,
+			ExpectedBody:   `{"error": "no result found"}`,
+		},
+		"Search failing": {
+			Url:            "/shows/search?title=Broken",
+			ExpectedStatus: http.StatusBadGateway,
+		},
+		"Search without a title": {
+			Url:            "/shows/search",
+			ExpectedStatus: http.StatusBadRequest,
+		},
+		"Show": {
+			Url:            "/shows/tt0944947",
+			ExpectedStatus: http.StatusOK,
+		},
+		"Show with an invalid ID": {
+			Url:            "/shows/game-of-thrones",
+			ExpectedStatus: http.StatusBadRequest,
+		},
+		"Show failing": {
+			Url:            "/shows/tt0000001",
+			ExpectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		source := &fakePremieres{}
+		server := NewServer(func() PremieresSource { return source }, fakeShows{}, nil, "", log.StandardLogger())
+		server.now = func() time.Time { return time.Date(2020, 6, 15, 14, 0, 0, 0, time.UTC) }
+		rec := httptest.NewRecorder()
+
+		//when
+		server.Handler().ServeHTTP(rec, httptest.NewRequest("GET", testdata.Url, nil))
+
+		//then
+		assert.Equal(t, testdata.ExpectedStatus, rec.Code, testcase)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"), testcase)
+		if testdata.ExpectedBody != "" {
+			assert.JSONEq(t, testdata.ExpectedBody, rec.Body.String(), testcase)
+		}
+		if testdata.ExpectedLastProcessedDate != "" {
+			assert.Equal(t, testdata.ExpectedLastProcessedDate, source.lastProcessedDate, testcase)
+		}
+	}
+}
+
+func Test_Server_Show(t *testing.T) {
+	//given
+	server := NewServer(newFakePremieres, fakeShows{}, nil, "", log.StandardLogger())
+	rec := httptest.NewRecorder()
+
+	//when
+	server.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/shows/tt0944947", nil))
+
+	//then
+	var show map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &show))
+	assert.Equal(t, "Game of Thrones", show["title"])
+	assert.Equal(t, "tt0944947", show["imdb_id"])
+	assert.Equal(t, 100.0, show["score"])
+}
+
+func Test_Server_Reports(t *testing.T) {
+	//given
+	started := make(chan bool)
+	finish := make(chan bool)
+	server := NewServer(newFakePremieres, fakeShows{}, func() application.Summary {
+		started <- true
+		<-finish
+		return application.Summary{Status: application.StatusOk, Matched: 3}
+	}, "secret", log.StandardLogger())
+	handler := server.Handler()
+
+	request := func(method, url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, url, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	//when
+	started1 := request("POST", "/reports")
+	<-started
+	during := request("GET", "/reports")
+	conflict := request("POST", "/reports")
+	finish <- true
+
+	//then
+	assert.Equal(t, http.StatusAccepted, started1.Code)
+	assert.JSONEq(t, `{"running": true}`, during.Body.String())
+	assert.Equal(t, http.StatusConflict, conflict.Code)
+	assert.JSONEq(t, `{"error": "a run is in progress"}`, conflict.Body.String())
+
+	//the run finishes in the background
+	require.Eventually(t, func() bool {
+		return strings.Contains(request("GET", "/reports").Body.String(), `"running":false`)
+	}, time.Second, 10*time.Millisecond)
+	assert.Contains(t, request("GET", "/reports").Body.String(), `"matched":3`)
+
+	//and the next one can be waited for
+	go func() {
+		<-started
+		finish <- true
+	}()
+	waited := request("POST", "/reports?wait=true")
+	assert.Equal(t, http.StatusOK, waited.Code)
+	assert.Contains(t, waited.Body.String(), `"status":"ok"`)
+}
+
+func Test_Server_Reports_Token(t *testing.T) {
+	testcases := map[string]struct {
+		Token          string
+		Authorization  string
+		ExpectedStatus int
+	}{
+		"No token configured": {
+			Authorization:  "Bearer ",
+			ExpectedStatus: http.StatusForbidden,
+		},
+		"Missing token": {
+			Token:          "secret",
+			ExpectedStatus: http.StatusUnauthorized,
+		},
+		"Wrong token": {
+			Token:          "secret",
+			Authorization:  "Bearer wrong",
+			ExpectedStatus: http.StatusUnauthorized,
+		},
+		"Valid token": {
+			Token:          "secret",
+			Authorization:  "Bearer secret",
+			ExpectedStatus: http.StatusOK,
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		runs := 0
+		server := NewServer(newFakePremieres, fakeShows{}, func() application.Summary {
+			runs++
+			return application.Summary{Status: application.StatusOk}
+		}, testdata.Token, log.StandardLogger())
+		req := httptest.NewRequest("POST", "/reports?wait=true", nil)
+		if testdata.Authorization != "" {
+			req.Header.Set("Authorization", testdata.Authorization)
+		}
+		rec := httptest.NewRecorder()
+
+		//when
+		server.Handler().ServeHTTP(rec, req)
+
+		//then
+		assert.Equal(t, testdata.ExpectedStatus, rec.Code, testcase)
+		if testdata.ExpectedStatus == http.StatusOK {
+			assert.Equal(t, 1, runs, testcase)
+		} else {
+			assert.Equal(t, 0, runs, testcase)
+		}
+	}
+}
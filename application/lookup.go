@@ -0,0 +1,45 @@
+package application
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/availability"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/enrich"
+	"github.com/ynori7/tvshows/metrics"
+	"github.com/ynori7/tvshows/premieres"
+	"github.com/ynori7/tvshows/ratings"
+	"github.com/ynori7/tvshows/recommend"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/watchlist"
+)
+
+// LookupTitle puts a single title through the same lookups and filters as the premieres in the report, to see
+// whether it would make it in and with which score. isNew says whether it premieres as a new series.
+func LookupTitle(conf config.Config, title string, isNew bool, logger log.FieldLogger, m *metrics.Metrics) (enrich.Result, error) {
+	wl, err := watchlist.Load(conf.Watchlist.Files)
+	if err != nil {
+		return enrich.Result{}, fmt.Errorf("error loading watchlist: %w", err)
+	}
+
+	ratingsHistory, err := ratings.Load(conf.Ratings.Files, conf.Ratings.DroppedFiles)
+	if err != nil {
+		return enrich.Result{}, fmt.Errorf("error loading ratings: %w", err)
+	}
+
+	availabilityResolver, err := availability.NewResolver(conf.Availability)
+	if err != nil {
+		return enrich.Result{}, fmt.Errorf("error loading availability data: %w", err)
+	}
+
+	imdbClient := tvshow.NewImdbClient(conf, logger, m)
+	profile := recommend.NewLazyProfile(func() recommend.Profile {
+		return recommend.NewProfile(recommend.LikedDocuments(ratingsHistory.Ratings(), imdbClient, conf.Recommendation, logger))
+	})
+
+	list := &premieres.PremiereList{
+		Premieres: []premieres.Premiere{{Title: title, IsNew: isNew}},
+	}
+	return enrich.NewEnricher(conf, imdbClient, list, wl, ratingsHistory, profile, availabilityResolver, logger, m).FilterAndEnrich(), nil
+}
@@ -1,7 +1,16 @@
 package application
 
+import (
+	"github.com/ynori7/tvshows/email"
+	"github.com/ynori7/tvshows/view"
+)
+
 type PremieresReport struct {
-	Html      string
-	StartDate string
-	EndDate   string
+	Html         string //email-safe, for the email
+	Output       string //in the requested format
+	StartDate    string
+	EndDate      string
+	Report       view.Report
+	InlineImages []email.InlineImage //the posters referred to by the html
+	Empty        bool                //nothing made it into the report, Html is the short email for that
 }
@@ -0,0 +1,21 @@
+package application
+
+import "github.com/ynori7/tvshows/config"
+
+// Options are the command line options of the runs, as opposed to the config
+type Options struct {
+	OutputPath        string //where the reports, posters, feed and calendar are saved
+	LastProcessedPath string //where the last processed date and the lock are saved
+	Format            string //of the saved report
+	SummaryFile       string //where the JSON summary of the run is saved, optional
+}
+
+// NewOptions takes the options of the runs from the flags
+func NewOptions(c config.CliConfig) Options {
+	return Options{
+		OutputPath:        c.OutputPath,
+		LastProcessedPath: c.LastProcessedPath,
+		Format:            c.Format,
+		SummaryFile:       c.SummaryFile,
+	}
+}
@@ -7,11 +7,32 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/archive"
+	"github.com/ynori7/tvshows/availability"
+	"github.com/ynori7/tvshows/calendar"
 	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/email"
 	"github.com/ynori7/tvshows/enrich"
+	"github.com/ynori7/tvshows/feed"
+	"github.com/ynori7/tvshows/metrics"
+	"github.com/ynori7/tvshows/poster"
 	"github.com/ynori7/tvshows/premieres"
+	"github.com/ynori7/tvshows/ratings"
+	"github.com/ynori7/tvshows/recommend"
+	"github.com/ynori7/tvshows/streamer"
 	"github.com/ynori7/tvshows/tvshow"
 	"github.com/ynori7/tvshows/view"
+	"github.com/ynori7/tvshows/watchlist"
+)
+
+// ErrNoNewSeries means that nothing made it into the report. It's a quiet week rather than a failure.
+var ErrNoNewSeries = fmt.Errorf("no new series")
+
+// The classes of failures, the errors returned by GeneratePremieresReport wrap one of them
+var (
+	ErrFetch      = fmt.Errorf("error fetching premieres")
+	ErrEnrichment = fmt.Errorf("error looking up the premieres")
+	ErrRender     = fmt.Errorf("error rendering report")
 )
 
 const (
@@ -22,21 +43,59 @@ const (
 
 type PremieresReporter struct {
 	conf            config.Config
+	opts            Options
 	premieresClient premieres.PremieresClient
+	watchlist       watchlist.Watchlist
+	ratingsHistory  ratings.History
+	subscriptions   map[streamer.Streamer]bool
+	availability    availability.Resolver
+	templates       view.Templates
+	logger          log.FieldLogger
+	metrics         *metrics.Metrics
 }
 
 func NewPremieresReporter(
 	conf config.Config,
+	opts Options,
 	premieresClient premieres.PremieresClient,
+	watchlist watchlist.Watchlist,
+	ratingsHistory ratings.History,
+	availability availability.Resolver,
+	templates view.Templates,
+	logger log.FieldLogger,
+	m *metrics.Metrics,
 ) PremieresReporter {
+	//Resolve the subscriptions by their aliases too
+	mapping := streamer.NewMapping(conf.Streamers)
+	subscriptions := make(map[streamer.Streamer]bool, len(conf.Subscriptions))
+	for _, s := range conf.Subscriptions {
+		if p, ok := mapping.Lookup(s); ok {
+			subscriptions[p.Name] = true
+		} else {
+			subscriptions[streamer.Streamer(s)] = true
+		}
+	}
+
 	return PremieresReporter{
 		conf:            conf,
+		opts:            opts,
 		premieresClient: premieresClient,
+		watchlist:       watchlist,
+		ratingsHistory:  ratingsHistory,
+		subscriptions:   subscriptions,
+		availability:    availability,
+		templates:       templates,
+		logger:          logger,
+		metrics:         m,
 	}
 }
 
+// GeneratePremieresReport fetches the premieres, filters them and renders the report. If nothing made it
+// into the report, the short email for that is returned together with ErrNoNewSeries. Once the premieres
+// were looked up, the report is also returned with the other errors, so that the run can be summarized.
 func (h PremieresReporter) GeneratePremieresReport() (*PremieresReport, error) {
-	logger := log.WithFields(log.Fields{"Logger": "GeneratePremieresReport"})
+	logger := h.logger.WithFields(log.Fields{"Logger": "GeneratePremieresReport"})
+	startedAt := time.Now()
 
 	lastProcessedDate := h.getLastProcessedDate()
 
@@ -44,42 +103,98 @@ func (h PremieresReporter) GeneratePremieresReport() (*PremieresReport, error) {
 	premieresList, err := h.premieresClient.GetPotentiallyInterestingPremieres(lastProcessedDate)
 	if err != nil {
 		logger.WithFields(log.Fields{"error": err}).Error("Error getting new premieres")
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrFetch, err)
 	}
+	h.metrics.PremieresScraped(premieres.Source, countScraped(premieresList))
+
+	imdbClient := tvshow.NewImdbClient(h.conf, h.logger, h.metrics)
+
+	//The profile of the shows we liked for the relevance scores, only built once a premiere is scored
+	profile := recommend.NewLazyProfile(func() recommend.Profile {
+		return recommend.NewProfile(recommend.LikedDocuments(h.ratingsHistory.Ratings(), imdbClient, h.conf.Recommendation, h.logger))
+	})
 
 	//Fetch the tv show details and filter
-	filterer := enrich.NewEnricher(h.conf, tvshow.NewImdbClient(h.conf), premieresList)
-	interestingSeries := filterer.FilterAndEnrich()
+	filterer := enrich.NewEnricher(h.conf, imdbClient, premieresList, h.watchlist, h.ratingsHistory, profile, h.availability, h.logger, h.metrics)
+	enriched := filterer.FilterAndEnrich()
 
-	if len(interestingSeries) == 0 {
-		return nil, fmt.Errorf("no new series")
+	report := h.newReport(premieresList, enriched, startedAt)
+	for _, f := range report.FilteredOut {
+		h.metrics.Filtered(f.Code)
 	}
 
-	//Split the new and returning series
-	newSeries := make([]tvshow.TvShow, 0)
-	returningSeries := make([]tvshow.TvShow, 0)
-	for _, series := range interestingSeries {
-		if series.IsNewSeries {
-			newSeries = append(newSeries, series)
-		} else {
-			returningSeries = append(returningSeries, series)
-		}
+	//If none of the lookups worked, IMDB is probably down or changed its pages
+	if allLookupsFailed(report.FilteredOut, len(premieresList.Premieres)) {
+		report.Run.FinishedAt = time.Now()
+		return &PremieresReport{StartDate: report.StartDate, EndDate: report.EndDate, Report: report},
+			fmt.Errorf("%w: all %d lookups failed", ErrEnrichment, len(premieresList.Premieres))
 	}
 
-	//Build HTML output
-	template := view.NewHtmlTemplate(newSeries, returningSeries)
-	out, err := template.ExecuteHtmlTemplate()
+	if len(report.Sections) == 0 {
+		return h.emptyReport(report)
+	}
+	report.Run.FinishedAt = time.Now()
+
+	//Save the posters as thumbnails next to the output
+	posters := make(map[string]poster.Poster)
+	if h.conf.Posters.Enabled {
+		posters = poster.NewDownloader(h.conf, h.opts.OutputPath, h.logger, h.metrics).Fetch(report.AllShows())
+	}
+
+	partial := &PremieresReport{StartDate: report.StartDate, EndDate: report.EndDate, Report: report}
+
+	//Build the email-safe HTML output for the email
+	out, images, err := h.buildEmail(report, posters)
 	if err != nil {
 		logger.WithFields(log.Fields{"error": err}).Error("Error generating html")
-		return nil, err
+		return partial, fmt.Errorf("%w: %w", ErrRender, err)
 	}
 
-	//Save HTML output to file
-	dateString := time.Now().Format(yyyyMMdd)
-	err = ioutil.WriteFile(fmt.Sprintf("%s/%s-%s.html", config.CliConf.OutputPath, h.conf.Title, dateString), []byte(out), 0644)
+	//Build the output in the requested format, which refers to the local thumbnails
+	renderer, err := view.NewRenderer(h.opts.Format, h.templates)
 	if err != nil {
-		logger.WithFields(log.Fields{"error": err}).Warn("Error saving html to file")
-		return nil, err
+		return partial, fmt.Errorf("%w: %w", ErrRender, err)
+	}
+	fileReport := report
+	if h.opts.Format != view.FormatJson {
+		fileReport = report.WithImages(func(show tvshow.TvShow) string {
+			if p, ok := posters[show.ImdbId]; ok {
+				return p.Path
+			}
+			return show.Image
+		})
+	}
+	fileOut, err := renderer.Render(fileReport)
+	if err != nil {
+		logger.WithFields(log.Fields{"error": err, "format": h.opts.Format}).Error("Error generating output")
+		return partial, fmt.Errorf("%w: %w", ErrRender, err)
+	}
+
+	//Save output to file
+	now := time.Now()
+	err = ioutil.WriteFile(fmt.Sprintf("%s/%s-%s.%s", h.opts.OutputPath, h.conf.Title, now.Format(yyyyMMdd), view.FileExtension(h.opts.Format)), []byte(fileOut), 0644)
+	if err != nil {
+		logger.WithFields(log.Fields{"error": err}).Warn("Error saving output to file")
+		return partial, fmt.Errorf("%w: %w", ErrRender, err)
+	}
+
+	//Keep a JSON copy for browsing and searching the history
+	if err := archive.NewArchive(h.opts.OutputPath).Save(report, now); err != nil {
+		logger.WithFields(log.Fields{"error": err}).Warn("Error archiving report")
+	}
+
+	//Add the shows to the feeds
+	if h.conf.Feed.Enabled {
+		if err := feed.NewFeed(h.conf, h.opts.OutputPath).Update(report); err != nil {
+			logger.WithFields(log.Fields{"error": err}).Warn("Error updating feeds")
+		}
+	}
+
+	//Add the premiere dates to the calendar
+	if h.conf.Calendar.Enabled {
+		if err := calendar.NewCalendar(h.conf, h.opts.OutputPath).Update(report); err != nil {
+			logger.WithFields(log.Fields{"error": err}).Warn("Error updating calendar")
+		}
 	}
 
 	//Mark where we left off
@@ -88,14 +203,114 @@ func (h PremieresReporter) GeneratePremieresReport() (*PremieresReport, error) {
 	}
 
 	return &PremieresReport{
-		Html:      out,
+		Html:         out,
+		InlineImages: images,
+		Output:       fileOut,
+		StartDate:    premieresList.StartDate,
+		EndDate:      premieresList.EndDate,
+		Report:       report,
+	}, nil
+}
+
+// emptyReport advances the last processed date, so the same premieres aren't looked at again, and
+// returns the report with the short email for the quiet week together with ErrNoNewSeries
+// newReport puts the looked up premieres into the sections of the report. Every scraped premiere ends up either
+// in the report or in the filtered list.
+func (h PremieresReporter) newReport(premieresList *premieres.PremiereList, enriched enrich.Result, startedAt time.Time) view.Report {
+	report := view.Report{
+		Title:     h.conf.Title,
 		StartDate: premieresList.StartDate,
 		EndDate:   premieresList.EndDate,
-	}, nil
+		Run: view.RunInfo{
+			StartedAt:        startedAt,
+			PremieresScraped: countScraped(premieresList),
+		},
+		Appendix: h.conf.Report.FilteredAppendix,
+	}
+	report.Sections, report.FilteredOut = h.buildSections(enriched)
+	return report
+}
+
+func (h PremieresReporter) emptyReport(report view.Report) (*PremieresReport, error) {
+	if err := h.updateLastProcessedDate(report.EndDate); err != nil {
+		h.logger.WithFields(log.Fields{"Logger": "emptyReport", "error": err}).Warn("Error updating last processed date")
+	}
+	report.Run.FinishedAt = time.Now()
+
+	//Archive it too, so that the history shows the quiet week
+	if err := archive.NewArchive(h.opts.OutputPath).Save(report, report.Run.FinishedAt); err != nil {
+		h.logger.WithFields(log.Fields{"Logger": "emptyReport", "error": err}).Warn("Error archiving report")
+	}
+
+	out, err := view.NewHtmlTemplate(report, h.templates).ExecuteEmptyTemplate()
+	if err != nil {
+		return &PremieresReport{StartDate: report.StartDate, EndDate: report.EndDate, Report: report, Empty: true},
+			fmt.Errorf("%w: %w", ErrRender, err)
+	}
+
+	return &PremieresReport{
+		Html:      out,
+		StartDate: report.StartDate,
+		EndDate:   report.EndDate,
+		Report:    report,
+		Empty:     true,
+	}, ErrNoNewSeries
+}
+
+// buildEmail renders the email with the posters attached inline, as many as fit into the size budget. The
+// others are still linked.
+func (h PremieresReporter) buildEmail(report view.Report, posters map[string]poster.Poster) (string, []email.InlineImage, error) {
+	out, err := view.NewHtmlTemplate(report, h.templates).ExecuteEmailTemplate()
+	if err != nil || len(posters) == 0 {
+		return out, nil, err
+	}
+
+	candidates := make([]email.InlineImage, 0, len(posters))
+	seen := make(map[string]bool, len(posters))
+	for _, show := range report.AllShows() {
+		if p, ok := posters[show.ImdbId]; ok && !seen[show.ImdbId] {
+			seen[show.ImdbId] = true //only attach it once
+			candidates = append(candidates, email.InlineImage{
+				ContentID:   p.ContentID(),
+				ContentType: "image/jpeg",
+				Filename:    show.ImdbId + ".jpg",
+				Data:        p.Data,
+			})
+		}
+	}
+	images := email.FitInlineImages(len(out), candidates, h.conf.Posters.GetEmailBudget())
+
+	attached := make(map[string]bool, len(images))
+	for _, img := range images {
+		attached[img.ContentID] = true
+	}
+	out, err = view.NewHtmlTemplate(report.WithImages(func(show tvshow.TvShow) string {
+		if p, ok := posters[show.ImdbId]; ok && attached[p.ContentID()] {
+			return "cid:" + p.ContentID()
+		}
+		return show.Image
+	}), h.templates).ExecuteEmailTemplate()
+
+	return out, images, err
+}
+
+// isAvailable checks whether the series is available on one of our subscribed services. Everything is
+// available when there's no subscription mode.
+func (h PremieresReporter) isAvailable(series tvshow.TvShow) bool {
+	if h.conf.SubscriptionMode != config.SubscriptionModeFilter && h.conf.SubscriptionMode != config.SubscriptionModeSection {
+		return true
+	}
+
+	for _, p := range series.StreamingOptions {
+		if h.subscriptions[p.Name] {
+			return true
+		}
+	}
+	return false
 }
 
 func (h PremieresReporter) getLastProcessedDate() string {
-	dat, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", config.CliConf.LastProcessedPath, lastProcessedFile))
+	dat, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", h.opts.LastProcessedPath, lastProcessedFile))
 	if err != nil || len(strings.TrimSpace(string(dat))) == 0 {
 		lastWeek := time.Now().Add(-1 * defaultDays * 24 * time.Hour)
 		return fmt.Sprintf("%s %d", lastWeek.Month(), lastWeek.Day())
@@ -105,5 +320,22 @@ func (h PremieresReporter) getLastProcessedDate() string {
 }
 
 func (h PremieresReporter) updateLastProcessedDate(date string) error {
-	return ioutil.WriteFile(fmt.Sprintf("%s/%s", config.CliConf.LastProcessedPath, lastProcessedFile), []byte(date), 0644)
+	return ioutil.WriteFile(fmt.Sprintf("%s/%s", h.opts.LastProcessedPath, lastProcessedFile), []byte(date), 0644)
+}
+
+// countScraped counts all of the premieres on the list, including the ones in the other genres
+func countScraped(premieresList *premieres.PremiereList) int {
+	return len(premieresList.Premieres) + len(premieresList.Skipped)
+}
+
+// allLookupsFailed is true if none of the premieres which were looked up were found on IMDB. A title which isn't
+// found is normal, but when none of them are, the search results most likely changed.
+func allLookupsFailed(filtered []view.FilteredTvShow, lookedUp int) bool {
+	failed := 0
+	for _, f := range filtered {
+		if f.Code == enrich.CodeLookupFailed || f.Code == enrich.CodeNotFound {
+			failed++
+		}
+	}
+	return lookedUp > 0 && failed == lookedUp
 }
@@ -0,0 +1,103 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/enrich"
+	"github.com/ynori7/tvshows/premieres"
+	"github.com/ynori7/tvshows/ratings"
+	"github.com/ynori7/tvshows/recommend"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+	"github.com/ynori7/tvshows/watchlist"
+)
+
+func Test_allLookupsFailed(t *testing.T) {
+	testcases := map[string]struct {
+		Filtered []view.FilteredTvShow
+		LookedUp int
+		Expected bool
+	}{
+		"All failed": {
+			Filtered: []view.FilteredTvShow{{Code: enrich.CodeLookupFailed}, {Code: enrich.CodeLookupFailed}},
+			LookedUp: 2,
+			Expected: true,
+		},
+		"None found": {
+			Filtered: []view.FilteredTvShow{{Code: enrich.CodeNotFound}, {Code: enrich.CodeLookupFailed}, {Code: enrich.CodeGenreMismatch}},
+			LookedUp: 2,
+			Expected: true,
+		},
+		"Some found": {
+			Filtered: []view.FilteredTvShow{{Code: enrich.CodeNotFound}, {Code: enrich.CodeScoreTooLow}},
+			LookedUp: 2,
+		},
+		"Some not in the report": {
+			Filtered: []view.FilteredTvShow{{Code: enrich.CodeNotFound}},
+			LookedUp: 2,
+		},
+		"Only the skipped genres": {
+			Filtered: []view.FilteredTvShow{{Code: enrich.CodeGenreMismatch}},
+			LookedUp: 0,
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//when
+		actual := allLookupsFailed(testdata.Filtered, testdata.LookedUp)
+
+		//then
+		assert.Equal(t, testdata.Expected, actual, testcase)
+	}
+}
+
+type fakeShows map[string]tvshow.TvShow
+
+func (f fakeShows) SearchForTvSeriesTitle(title string) (string, error) {
+	if _, ok := f[title]; !ok {
+		return "", tvshow.ErrNoResult
+	}
+	return title, nil
+}
+
+func (f fakeShows) GetTvShowData(link string) (*tvshow.TvShow, error) {
+	show := f[link]
+	return &show, nil
+}
+
+func Test_newReport_SummaryAddsUp(t *testing.T) {
+	//given
+	list := &premieres.PremiereList{
+		StartDate: "June 4",
+		EndDate:   "June 11",
+		Premieres: []premieres.Premiere{
+			{Title: "Game of Thrones", Genres: []string{"Drama"}},
+			{Title: "Sweet Magnolias", Genres: []string{"Drama"}},
+			{Title: "Unknown", Genres: []string{"Comedy"}},
+		},
+		Skipped: []premieres.Premiere{
+			{Title: "Mark vs. The Mountain", Genres: []string{"Reality"}},
+		},
+	}
+	shows := fakeShows{
+		"Game of Thrones": {Title: "Game of Thrones", ImdbId: "tt0944947", Link: "Game of Thrones", Score: 100},
+		"Sweet Magnolias": {Title: "Sweet Magnolias", ImdbId: "tt10240086", Link: "Sweet Magnolias", Score: 10},
+	}
+	conf := config.Config{MainGenres: []string{"Drama", "Comedy"}}
+	enriched := enrich.NewEnricher(conf, shows, list, watchlist.New(), ratings.New(), recommend.Profile{}, nil, log.StandardLogger(), nil).FilterAndEnrich()
+	reporter := PremieresReporter{conf: conf, logger: log.StandardLogger()}
+
+	//when
+	report := reporter.newReport(list, enriched, time.Now())
+	summary := NewSummary(&PremieresReport{Report: report}, nil, time.Now())
+
+	//then
+	assert.Equal(t, 4, summary.PremieresScraped)
+	assert.Equal(t, 1, summary.Matched)
+	assert.Equal(t, 3, summary.Filtered)
+	assert.Equal(t, summary.PremieresScraped, summary.Matched+summary.Filtered, "Every premiere is either reported or filtered")
+}
@@ -0,0 +1,169 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/availability"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/daemon"
+	"github.com/ynori7/tvshows/email"
+	"github.com/ynori7/tvshows/metrics"
+	"github.com/ynori7/tvshows/premieres"
+	"github.com/ynori7/tvshows/ratings"
+	"github.com/ynori7/tvshows/view"
+	"github.com/ynori7/tvshows/watchlist"
+)
+
+// The exit codes of the runs, so that monitoring can tell the failures apart
+const (
+	ExitOk          = 0
+	ExitConfig      = 2  //the config or one of the files it refers to is broken
+	ExitFetch       = 3  //the premieres couldn't be fetched
+	ExitEnrichment  = 4  //none of the premieres could be looked up
+	ExitRender      = 5  //the report couldn't be rendered or saved
+	ExitDelivery    = 6  //the email couldn't be sent
+	ExitLocked      = 7  //another run is in progress
+	ExitNoNewSeries = 10 //a quiet week rather than a failure
+)
+
+const lockFile = "tvshows.lock"
+
+// Runner does the runs, from loading the watchlist to sending the email, and records how they ended
+type Runner struct {
+	conf      config.Config
+	opts      Options
+	templates view.Templates
+	logger    log.FieldLogger
+	metrics   *metrics.Metrics
+	out       io.Writer //where the text report is shown, if at all
+}
+
+func NewRunner(conf config.Config, opts Options, templates view.Templates, logger log.FieldLogger, m *metrics.Metrics, out io.Writer) *Runner {
+	return &Runner{
+		conf:      conf,
+		opts:      opts,
+		templates: templates,
+		logger:    logger,
+		metrics:   m,
+		out:       out,
+	}
+}
+
+// Run does a run and returns its exit code and summary
+func (r *Runner) Run() (int, Summary) {
+	startedAt := time.Now()
+	logger := r.logger.WithFields(log.Fields{"Logger": "Run"})
+
+	//Don't overlap with another run, since they share the last processed date
+	lock, err := daemon.AcquireLock(filepath.Join(r.opts.LastProcessedPath, lockFile))
+	if err != nil {
+		return r.Failed(ExitLocked, NewSummary(nil, err, startedAt), "Error acquiring the lock")
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			logger.WithFields(log.Fields{"error": err}).Warn("Error releasing the lock")
+		}
+	}()
+
+	//Get the watchlist
+	wl, err := watchlist.Load(r.conf.Watchlist.Files)
+	if err != nil {
+		return r.Failed(ExitConfig, NewSummary(nil, err, startedAt), "Error loading watchlist")
+	}
+
+	//Get the personal ratings
+	ratingsHistory, err := ratings.Load(r.conf.Ratings.Files, r.conf.Ratings.DroppedFiles)
+	if err != nil {
+		return r.Failed(ExitConfig, NewSummary(nil, err, startedAt), "Error loading ratings")
+	}
+
+	//Get the regional availability source
+	availabilityResolver, err := availability.NewResolver(r.conf.Availability)
+	if err != nil {
+		return r.Failed(ExitConfig, NewSummary(nil, err, startedAt), "Error loading availability data")
+	}
+
+	premieresReporter := NewPremieresReporter(r.conf, r.opts, premieres.NewPremieresClient(r.conf), wl, ratingsHistory, availabilityResolver, r.templates, r.logger, r.metrics)
+	newPremieresReport, err := premieresReporter.GeneratePremieresReport()
+	summary := NewSummary(newPremieresReport, err, startedAt)
+	switch {
+	case errors.Is(err, ErrNoNewSeries):
+		logger.Info("Nothing interesting premiered")
+		if r.conf.Email.Enabled && r.conf.Report.EmptyReport == config.EmptyReportEmail {
+			if err := r.sendMail(email.GetNoNewReleasesSubjectLine(newPremieresReport.StartDate, newPremieresReport.EndDate), newPremieresReport); err != nil {
+				return r.Failed(ExitDelivery, notDelivered(summary, err, startedAt), "Error sending email")
+			}
+		}
+		return ExitNoNewSeries, summary
+	case errors.Is(err, ErrFetch):
+		return r.Failed(ExitFetch, summary, "Error getting new premieres")
+	case errors.Is(err, ErrEnrichment):
+		return r.Failed(ExitEnrichment, summary, "Error looking up the premieres")
+	case err != nil:
+		return r.Failed(ExitRender, summary, "Error generating the report")
+	}
+
+	//Show it in the terminal too
+	if r.opts.Format == view.FormatText && r.out != nil {
+		fmt.Fprint(r.out, newPremieresReport.Output)
+	}
+
+	if r.conf.Email.Enabled {
+		if err := r.sendMail(email.GetNewReleasesSubjectLine(newPremieresReport.StartDate, newPremieresReport.EndDate), newPremieresReport); err != nil {
+			return r.Failed(ExitDelivery, notDelivered(summary, err, startedAt), "Error sending email")
+		}
+	}
+
+	return ExitOk, summary
+}
+
+func (r *Runner) sendMail(subject string, report *PremieresReport) error {
+	err := email.NewMailer(r.conf).SendMail(subject, report.Html, report.InlineImages...)
+	r.metrics.EmailSent(err)
+	return err
+}
+
+// notDelivered marks the summary of a run whose report couldn't be sent
+func notDelivered(summary Summary, err error, startedAt time.Time) Summary {
+	summary.Status = StatusNotDelivered
+	summary.Error = err.Error()
+	summary.Errors++
+	summary.DurationSeconds = time.Since(startedAt).Seconds()
+	return summary
+}
+
+// Failed logs the failure and passes on the exit code and summary
+func (r *Runner) Failed(code int, summary Summary, msg string) (int, Summary) {
+	r.logger.WithFields(log.Fields{"Logger": "Failed", "code": code, "error": summary.Error}).Error(msg)
+	return code, summary
+}
+
+// Finish saves the summary and the metrics, if requested, and returns the summary with the exit code
+func (r *Runner) Finish(code int, summary Summary) Summary {
+	logger := r.logger.WithFields(log.Fields{"Logger": "Finish", "code": code})
+
+	summary.ExitCode = code
+	if r.opts.SummaryFile != "" {
+		if err := summary.Write(r.opts.SummaryFile); err != nil {
+			logger.WithFields(log.Fields{"error": err}).Error("Error saving summary")
+		}
+	}
+
+	r.metrics.RunFinished(summary.Status, time.Duration(summary.DurationSeconds*float64(time.Second)))
+	if r.conf.Metrics.Textfile != "" {
+		if err := r.metrics.WriteTextfile(r.conf.Metrics.Textfile); err != nil {
+			logger.WithFields(log.Fields{"error": err}).Error("Error saving metrics")
+		}
+	}
+	return summary
+}
+
+// Succeeded is true for the exit codes of runs which worked, including the quiet weeks
+func Succeeded(code int) bool {
+	return code == ExitOk || code == ExitNoNewSeries
+}
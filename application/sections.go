@@ -0,0 +1,215 @@
+package application
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/enrich"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+const otherGroup = "Other"
+
+// group is a section of the grouped shows, with a key to order the sections by
+type group struct {
+	section view.Section
+	order   string
+}
+
+// buildSections splits the shows into the report sections, in the order they're shown, and lists the ones
+// which were left out
+func (h PremieresReporter) buildSections(enriched enrich.Result) ([]view.Section, []view.FilteredTvShow) {
+	logger := h.logger.WithFields(log.Fields{"Logger": "buildSections"})
+
+	sections := make([]view.Section, 0)
+	filteredOut := make([]view.FilteredTvShow, 0)
+
+	//Split off the watchlist and the ones which aren't on our services
+	watchlist := make([]tvshow.TvShow, 0)
+	unavailable := make([]tvshow.TvShow, 0)
+	rest := make([]tvshow.TvShow, 0)
+	for _, series := range enriched.Series {
+		if series.OnWatchlist {
+			watchlist = append(watchlist, series)
+		} else if !h.isAvailable(series) {
+			if h.conf.SubscriptionMode == config.SubscriptionModeSection {
+				unavailable = append(unavailable, series)
+			} else {
+				logger.WithFields(log.Fields{"Title": series.Title}).Info("Series is not available on our services")
+				filteredOut = append(filteredOut, view.FilteredTvShow{
+					Title:  series.Title,
+					Code:   enrich.CodeUnavailable,
+					Reason: "not available on our services",
+					Score:  series.Score,
+					Link:   series.Link,
+				})
+			}
+		} else {
+			rest = append(rest, series)
+		}
+	}
+	sections = appendSection(sections, view.Section{Id: view.SectionWatchlist, Title: "From your watchlist", Shows: watchlist})
+
+	if h.conf.Report.TopPick && len(rest) > 0 {
+		top := h.topPick(rest)
+		sections = append(sections, view.Section{Id: view.SectionTopPick, Title: "Top pick of the week", Shows: []tvshow.TvShow{rest[top]}})
+		others := make([]tvshow.TvShow, 0, len(rest)-1)
+		others = append(others, rest[:top]...)
+		rest = append(others, rest[top+1:]...)
+	}
+
+	for _, g := range h.group(rest) {
+		sections = appendSection(sections, g)
+	}
+
+	//The best of the shows which were just under the score threshold. They're identified by the IMDB link,
+	//since the title on IMDB can differ from the one on the premieres list.
+	mentioned := make(map[string]bool)
+	mentions := make([]tvshow.TvShow, 0)
+	for _, series := range enriched.NearMisses {
+		if len(mentions) >= h.conf.Report.HonorableMentions {
+			break
+		}
+		if h.isAvailable(series) {
+			mentions = append(mentions, series)
+			mentioned[series.Link] = true
+		}
+	}
+	sections = appendSection(sections, view.Section{Id: view.SectionHonorableMentions, Title: "Honorable mentions", Shows: mentions})
+
+	sections = appendSection(sections, view.Section{Id: view.SectionUnavailable, Title: "Not on your services", Shows: unavailable})
+
+	for _, r := range enriched.Rejected {
+		if r.Link == "" || !mentioned[r.Link] {
+			filteredOut = append(filteredOut, view.FilteredTvShow{Title: r.Title, Code: r.Code, Reason: r.Reason, Score: r.Score, Link: r.Link})
+		}
+	}
+
+	//The best ones first, since those are the most likely to be wrongly filtered out
+	sort.SliceStable(filteredOut, func(i, j int) bool {
+		if filteredOut[i].Score != filteredOut[j].Score {
+			return filteredOut[i].Score > filteredOut[j].Score
+		}
+		return filteredOut[i].Title < filteredOut[j].Title
+	})
+
+	return sections, filteredOut
+}
+
+func appendSection(sections []view.Section, section view.Section) []view.Section {
+	if len(section.Shows) == 0 {
+		return sections
+	}
+	return append(sections, section)
+}
+
+// topPick returns the index of the best show, by relevance if the report is sorted by it or else by score
+func (h PremieresReporter) topPick(shows []tvshow.TvShow) int {
+	best := 0
+	for i, s := range shows {
+		if h.conf.Report.SortBy == config.SortByRelevance && s.RelevanceScore != shows[best].RelevanceScore {
+			if s.RelevanceScore > shows[best].RelevanceScore {
+				best = i
+			}
+			continue
+		}
+		if s.Score > shows[best].Score {
+			best = i
+		}
+	}
+	return best
+}
+
+// group splits the shows into sections by the configured attribute, keeping the order of the shows
+func (h PremieresReporter) group(shows []tvshow.TvShow) []view.Section {
+	groups := make([]*group, 0)
+	byKey := make(map[string]*group)
+	for _, s := range shows {
+		g := h.groupOf(s)
+		if existing, ok := byKey[g.section.Id]; ok {
+			g = existing
+		} else {
+			byKey[g.section.Id] = g
+			groups = append(groups, g)
+		}
+		g.section.Shows = append(g.section.Shows, s)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].order < groups[j].order
+	})
+
+	sections := make([]view.Section, 0, len(groups))
+	for _, g := range groups {
+		sections = append(sections, g.section)
+	}
+	return sections
+}
+
+// groupOf returns the (empty) group the show belongs to
+func (h PremieresReporter) groupOf(s tvshow.TvShow) *group {
+	switch h.conf.Report.GroupBy {
+	case config.GroupByGenre:
+		genre, order := otherGroup, "2"
+		for i, g := range h.conf.MainGenres {
+			if containsFold(s.Genres, g) {
+				genre, order = g, fmt.Sprintf("0%03d", i) //in the order of the config
+				break
+			}
+		}
+		if genre == otherGroup && len(s.Genres) > 0 {
+			genre, order = s.Genres[0], "1"+strings.ToLower(s.Genres[0])
+		}
+		return newGroup("genre-"+sectionId(genre), genre, order)
+	case config.GroupByStreamer:
+		name := otherGroup
+		if len(s.StreamingOptions) > 0 {
+			name = string(s.StreamingOptions[0].Name)
+			for _, p := range s.StreamingOptions {
+				if p.IsStreaming() { //prefer the streaming services, since that's where we'd watch it
+					name = string(p.Name)
+					break
+				}
+			}
+		}
+		order := "0" + strings.ToLower(name)
+		if name == otherGroup {
+			order = "1"
+		}
+		return newGroup("streamer-"+sectionId(name), name, order)
+	case config.GroupByDate:
+		if s.PremiereDate.IsZero() {
+			return newGroup("date-unknown", "Date unknown", "1")
+		}
+		return newGroup("date-"+s.PremiereDate.Format("2006-01-02"), s.PremiereDate.Format("Monday, Jan 2"), "0"+s.PremiereDate.Format(time.RFC3339))
+	}
+
+	if s.IsNewSeries {
+		return newGroup(view.SectionNew, "New Series", "1")
+	}
+	return newGroup(view.SectionReturning, "Returning Series", "0")
+}
+
+func newGroup(id string, title string, order string) *group {
+	return &group{section: view.Section{Id: id, Title: title, Shows: make([]tvshow.TvShow, 0)}, order: order}
+}
+
+func sectionId(name string) string {
+	return strings.ToLower(strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}), "-"))
+}
+
+func containsFold(list []string, s string) bool {
+	for _, l := range list {
+		if strings.EqualFold(strings.TrimSpace(l), s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/enrich"
+	"github.com/ynori7/tvshows/premieres"
+	"github.com/ynori7/tvshows/ratings"
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+	"github.com/ynori7/tvshows/watchlist"
+)
+
+func Test_buildSections(t *testing.T) {
+	netflix := []streamer.Provider{{Name: streamer.Netflix, Type: streamer.Streaming}}
+	hbo := []streamer.Provider{{Name: "HBO", Type: streamer.Network}, {Name: "HBO Max", Type: streamer.Streaming}}
+	enriched := enrich.Result{
+		Series: []tvshow.TvShow{
+			{Title: "Game of Thrones", Score: 100, RelevanceScore: 20, Genres: []string{"Action", "Drama"}, StreamingOptions: hbo, PremiereDate: time.Date(2020, 6, 9, 0, 0, 0, 0, time.UTC)},
+			{Title: "Élite", Score: 70, RelevanceScore: 90, IsNewSeries: true, Genres: []string{"Thriller", "Drama"}, StreamingOptions: netflix, PremiereDate: time.Date(2020, 6, 8, 0, 0, 0, 0, time.UTC)},
+			{Title: "#BlackAF", Score: 39, IsNewSeries: true, Genres: []string{"Comedy"}, StreamingOptions: netflix},
+			{Title: "Wanted", Score: 50, OnWatchlist: true, Genres: []string{"Comedy"}},
+			{Title: "Unknown", Score: 45, Genres: []string{"Crime"}},
+		},
+		Rejected: []enrich.Rejection{
+			{Title: "Sweet Magnolias (2020)", Reason: "score is too low: 35", Score: 35, Link: "https://www.imdb.com/title/tt10240086/"}, //the title on the premieres list differs
+			{Title: "The Bachelor", Reason: "score is too low: 10"},
+		},
+		NearMisses: []tvshow.TvShow{
+			{Title: "Sweet Magnolias", Score: 35, StreamingOptions: netflix, Link: "https://www.imdb.com/title/tt10240086/"},
+		},
+	}
+
+	testcases := map[string]struct {
+		Report           config.Config
+		ExpectedSections map[string][]string
+		ExpectedOrder    []string
+		ExpectedFiltered []string
+	}{
+		"Default": {
+			Report:        config.Config{},
+			ExpectedOrder: []string{view.SectionWatchlist, view.SectionReturning, view.SectionNew},
+			ExpectedSections: map[string][]string{
+				view.SectionReturning: {"Game of Thrones", "Unknown"},
+				view.SectionNew:       {"Élite", "#BlackAF"},
+			},
+			ExpectedFiltered: []string{"Sweet Magnolias (2020)", "The Bachelor"},
+		},
+		"Top pick by relevance and honorable mentions": {
+			Report:        config.Config{Report: config.Report{SortBy: config.SortByRelevance, TopPick: true, HonorableMentions: 2}},
+			ExpectedOrder: []string{view.SectionWatchlist, view.SectionTopPick, view.SectionReturning, view.SectionNew, view.SectionHonorableMentions},
+			ExpectedSections: map[string][]string{
+				view.SectionTopPick:           {"Élite"},
+				view.SectionNew:               {"#BlackAF"},
+				view.SectionHonorableMentions: {"Sweet Magnolias"},
+			},
+			ExpectedFiltered: []string{"The Bachelor"},
+		},
+		"By genre": {
+			Report:        config.Config{MainGenres: []string{"Comedy", "Drama"}, Report: config.Report{GroupBy: config.GroupByGenre}},
+			ExpectedOrder: []string{view.SectionWatchlist, "genre-comedy", "genre-drama", "genre-crime"},
+			ExpectedSections: map[string][]string{
+				"genre-comedy": {"#BlackAF"},
+				"genre-drama":  {"Game of Thrones", "Élite"},
+			},
+		},
+		"By streamer": {
+			Report:        config.Config{Report: config.Report{GroupBy: config.GroupByStreamer}},
+			ExpectedOrder: []string{view.SectionWatchlist, "streamer-hbo-max", "streamer-netflix", "streamer-other"},
+			ExpectedSections: map[string][]string{
+				"streamer-netflix": {"Élite", "#BlackAF"},
+			},
+		},
+		"By date": {
+			Report:        config.Config{Report: config.Report{GroupBy: config.GroupByDate}},
+			ExpectedOrder: []string{view.SectionWatchlist, "date-2020-06-08", "date-2020-06-09", "date-unknown"},
+			ExpectedSections: map[string][]string{
+				"date-2020-06-08": {"Élite"},
+				"date-unknown":    {"#BlackAF", "Unknown"},
+			},
+		},
+		"Subscriptions filtered": {
+			Report:        config.Config{SubscriptionMode: config.SubscriptionModeFilter},
+			ExpectedOrder: []string{view.SectionWatchlist, view.SectionNew},
+			ExpectedSections: map[string][]string{
+				view.SectionWatchlist: {"Wanted"},
+				view.SectionNew:       {"Élite", "#BlackAF"},
+			},
+			ExpectedFiltered: []string{"Game of Thrones", "Unknown", "Sweet Magnolias (2020)", "The Bachelor"},
+		},
+		"Subscriptions": {
+			Report:        config.Config{SubscriptionMode: config.SubscriptionModeSection},
+			ExpectedOrder: []string{view.SectionWatchlist, view.SectionNew, view.SectionUnavailable},
+			ExpectedSections: map[string][]string{
+				view.SectionNew:         {"Élite", "#BlackAF"},
+				view.SectionUnavailable: {"Game of Thrones", "Unknown"},
+			},
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		reporter := PremieresReporter{conf: testdata.Report, subscriptions: map[streamer.Streamer]bool{streamer.Netflix: true}, logger: log.StandardLogger()}
+
+		//when
+		sections, filtered := reporter.buildSections(enriched)
+
+		//then
+		ids := make([]string, 0)
+		titles := make(map[string][]string)
+		for _, s := range sections {
+			ids = append(ids, s.Id)
+			for _, show := range s.Shows {
+				titles[s.Id] = append(titles[s.Id], show.Title)
+			}
+		}
+		assert.Equal(t, testdata.ExpectedOrder, ids, testcase)
+		for id, expected := range testdata.ExpectedSections {
+			assert.Equal(t, expected, titles[id], testcase)
+		}
+		if testdata.ExpectedFiltered != nil {
+			filteredTitles := make([]string, 0)
+			for _, f := range filtered {
+				filteredTitles = append(filteredTitles, f.Title)
+			}
+			assert.Equal(t, testdata.ExpectedFiltered, filteredTitles, testcase)
+		}
+	}
+}
+
+func Test_isAvailable(t *testing.T) {
+	//given
+	conf := config.Config{Subscriptions: []string{"Amazon Prime", "max", "Local TV"}}
+
+	testcases := map[string]struct {
+		Mode      string
+		Providers []streamer.Provider
+		Expected  bool
+	}{
+		"Alias of a subscription": {
+			Mode:      config.SubscriptionModeFilter,
+			Providers: []streamer.Provider{{Name: streamer.Amazon, Type: streamer.Streaming}},
+			Expected:  true,
+		},
+		"Alias in another case": {
+			Mode:      config.SubscriptionModeFilter,
+			Providers: []streamer.Provider{{Name: "HBO", Type: streamer.Network}, {Name: "HBO Max", Type: streamer.Streaming}},
+			Expected:  true,
+		},
+		"Unknown subscription by its name": {
+			Mode:      config.SubscriptionModeSection,
+			Providers: []streamer.Provider{{Name: "Local TV", Type: streamer.Network}},
+			Expected:  true,
+		},
+		"Not subscribed": {
+			Mode:      config.SubscriptionModeFilter,
+			Providers: []streamer.Provider{{Name: streamer.Netflix, Type: streamer.Streaming}},
+			Expected:  false,
+		},
+		"No providers": {
+			Mode:     config.SubscriptionModeFilter,
+			Expected: false,
+		},
+		"No subscription mode": {
+			Mode:     "",
+			Expected: true,
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		conf.SubscriptionMode = testdata.Mode
+		reporter := NewPremieresReporter(conf, Options{}, premieres.PremieresClient{}, watchlist.New(), ratings.New(), nil, view.Templates{}, log.StandardLogger(), nil)
+
+		//when
+		actual := reporter.isAvailable(tvshow.TvShow{Title: testcase, StreamingOptions: testdata.Providers})
+
+		//then
+		assert.Equal(t, testdata.Expected, actual, testcase)
+	}
+}
@@ -0,0 +1,76 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"time"
+
+	"github.com/ynori7/tvshows/enrich"
+	"github.com/ynori7/tvshows/view"
+)
+
+// The statuses of a run
+const (
+	StatusOk           = "ok"
+	StatusNoNewSeries  = "no_new_series"
+	StatusFailed       = "failed"
+	StatusNotDelivered = "not_delivered" //the report was generated, but the email couldn't be sent
+)
+
+// Summary is the machine-readable outcome of a run, for monitoring
+type Summary struct {
+	Status           string  `json:"status"`
+	ExitCode         int     `json:"exit_code"`
+	Error            string  `json:"error,omitempty"`
+	PremieresScraped int     `json:"premieres_scraped"`
+	Matched          int     `json:"matched"`  //the shows in the report
+	Filtered         int     `json:"filtered"` //the premieres which were left out, including the failed lookups
+	Errors           int     `json:"errors"`   //failed lookups and the error which ended the run, if any
+	DurationSeconds  float64 `json:"duration_seconds"`
+}
+
+// NewSummary summarizes the run which started at startedAt. The report is nil if the run failed before the
+// premieres were looked up.
+func NewSummary(report *PremieresReport, err error, startedAt time.Time) Summary {
+	s := Summary{
+		Status:          StatusOk,
+		DurationSeconds: time.Since(startedAt).Seconds(),
+	}
+
+	if report != nil {
+		s.PremieresScraped = report.Report.Run.PremieresScraped
+		s.Matched = len(report.Report.AllShows())
+		s.Filtered = len(report.Report.FilteredOut)
+		s.Errors = countLookupFailures(report.Report.FilteredOut)
+	}
+
+	if errors.Is(err, ErrNoNewSeries) {
+		s.Status = StatusNoNewSeries
+	} else if err != nil {
+		s.Status = StatusFailed
+		s.Error = err.Error()
+		s.Errors++
+	}
+
+	return s
+}
+
+// Write saves the summary as JSON
+func (s Summary) Write(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func countLookupFailures(filtered []view.FilteredTvShow) int {
+	count := 0
+	for _, f := range filtered {
+		if f.Code == enrich.CodeLookupFailed {
+			count++
+		}
+	}
+	return count
+}
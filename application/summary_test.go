@@ -0,0 +1,77 @@
+package application
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/enrich"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+func Test_NewSummary(t *testing.T) {
+	report := &PremieresReport{Report: view.Report{
+		Run:      view.RunInfo{PremieresScraped: 5},
+		Sections: []view.Section{{Id: view.SectionNew, Shows: []tvshow.TvShow{{Title: "#BlackAF"}, {Title: "Élite"}}}},
+		FilteredOut: []view.FilteredTvShow{
+			{Title: "Sweet Magnolias", Code: enrich.CodeScoreTooLow},
+			{Title: "The Bachelor", Code: enrich.CodeLookupFailed},
+			{Title: "Mark vs. The Mountain", Code: enrich.CodeGenreMismatch},
+		},
+	}}
+
+	testcases := map[string]struct {
+		Report   *PremieresReport
+		Err      error
+		Expected Summary
+	}{
+		"Successful run": {
+			Report:   report,
+			Expected: Summary{Status: StatusOk, PremieresScraped: 5, Matched: 2, Filtered: 3, Errors: 1},
+		},
+		"Quiet week": {
+			Report:   &PremieresReport{Report: view.Report{Run: view.RunInfo{PremieresScraped: 5}}},
+			Err:      ErrNoNewSeries,
+			Expected: Summary{Status: StatusNoNewSeries, PremieresScraped: 5},
+		},
+		"Failed before the lookups": {
+			Err:      fmt.Errorf("%w: %w", ErrFetch, fmt.Errorf("timeout")),
+			Expected: Summary{Status: StatusFailed, Error: "error fetching premieres: timeout", Errors: 1},
+		},
+		"Failed after the lookups": {
+			Report:   report,
+			Err:      fmt.Errorf("%w: %w", ErrRender, fmt.Errorf("broken")),
+			Expected: Summary{Status: StatusFailed, Error: "error rendering report: broken", PremieresScraped: 5, Matched: 2, Filtered: 3, Errors: 2},
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//when
+		actual := NewSummary(testdata.Report, testdata.Err, time.Now())
+
+		//then
+		assert.True(t, actual.DurationSeconds >= 0, testcase)
+		actual.DurationSeconds = 0
+		assert.Equal(t, testdata.Expected, actual, testcase)
+	}
+}
+
+func Test_Summary_Write(t *testing.T) {
+	//given
+	path := filepath.Join(t.TempDir(), "summary.json")
+	summary := Summary{Status: StatusOk, ExitCode: 0, PremieresScraped: 5, Matched: 2, Filtered: 3, Errors: 1, DurationSeconds: 1.5}
+
+	//when
+	err := summary.Write(path)
+
+	//then
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status": "ok", "exit_code": 0, "premieres_scraped": 5, "matched": 2, "filtered": 3, "errors": 1, "duration_seconds": 1.5}`, string(data))
+}
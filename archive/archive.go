@@ -0,0 +1,217 @@
+package archive
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+const (
+	Dir      = "archive" //the directory in the output path where the reports are kept as JSON
+	yyyyMMdd = "20060102"
+)
+
+// reportFileRegex matches the saved reports, <title>-<yyyymmdd>.<extension>
+var reportFileRegex = regexp.MustCompile(`^(.+)-(\d{8})\.(html|json|md|txt)$`)
+
+// Archive is the history of the reports in the output directory
+type Archive struct {
+	outputPath string
+}
+
+// Week is a run of the report, with the files which were saved for it
+type Week struct {
+	Key      string            //<title>-<yyyymmdd>
+	Title    string            //of the config
+	Date     time.Time         //when the report was generated
+	Files    map[string]string //the saved reports by their extension, relative to the output path
+	Archived bool              //there's a JSON copy in the archive, so it can be rendered and searched
+}
+
+// Match is a show which was in one of the reports
+type Match struct {
+	Week    Week
+	Section string
+	Show    tvshow.TvShow
+}
+
+// Query is what to search for. Empty fields match everything.
+type Query struct {
+	Title    string //part of the title
+	Genre    string
+	Streamer string //a streaming service or network
+}
+
+func NewArchive(outputPath string) Archive {
+	return Archive{outputPath: outputPath}
+}
+
+// Save keeps a JSON copy of the report, so that it can be browsed and searched later
+func (a Archive) Save(report view.Report, date time.Time) error {
+	out, err := view.NewJsonTemplate(report).ExecuteJsonTemplate()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Join(a.outputPath, Dir), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(a.outputPath, Dir, weekKey(report.Title, date)+".json"), []byte(out), 0644)
+}
+
+// Weeks lists the reports in the output directory and the archive, newest first
+func (a Archive) Weeks() ([]Week, error) {
+	weeks := make(map[string]*Week)
+
+	add := func(dir string, archived bool) error {
+		files, err := ioutil.ReadDir(filepath.Join(a.outputPath, dir))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, f := range files {
+			matches := reportFileRegex.FindStringSubmatch(f.Name())
+			if f.IsDir() || matches == nil || (archived && matches[3] != "json") {
+				continue
+			}
+			date, err := time.Parse(yyyyMMdd, matches[2])
+			if err != nil {
+				continue
+			}
+
+			key := weekKey(matches[1], date)
+			w, ok := weeks[key]
+			if !ok {
+				w = &Week{Key: key, Title: matches[1], Date: date, Files: make(map[string]string)}
+				weeks[key] = w
+			}
+			if archived {
+				w.Archived = true
+			} else {
+				w.Files[matches[3]] = f.Name()
+			}
+		}
+		return nil
+	}
+	if err := add(".", false); err != nil {
+		return nil, err
+	}
+	if err := add(Dir, true); err != nil {
+		return nil, err
+	}
+
+	list := make([]Week, 0, len(weeks))
+	for _, w := range weeks {
+		list = append(list, *w)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Date.Equal(list[j].Date) {
+			return list[i].Date.After(list[j].Date)
+		}
+		return list[i].Title < list[j].Title
+	})
+	return list, nil
+}
+
+// Week returns the week with the key
+func (a Archive) Week(key string) (Week, error) {
+	weeks, err := a.Weeks()
+	if err != nil {
+		return Week{}, err
+	}
+	for _, w := range weeks {
+		if w.Key == key {
+			return w, nil
+		}
+	}
+	return Week{}, os.ErrNotExist
+}
+
+// Load reads the archived report of the week
+func (a Archive) Load(week Week) (view.Report, error) {
+	if !week.Archived {
+		return view.Report{}, fmt.Errorf("%s isn't archived", week.Key)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(a.outputPath, Dir, week.Key+".json"))
+	if err != nil {
+		return view.Report{}, err
+	}
+	return view.ParseJsonReport(data)
+}
+
+// Search looks for the shows in all of the archived reports, newest first. Reports which can't be read
+// are skipped.
+func (a Archive) Search(q Query) ([]Match, error) {
+	weeks, err := a.Weeks()
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0)
+	for _, w := range weeks {
+		if !w.Archived {
+			continue
+		}
+		report, err := a.Load(w)
+		if err != nil {
+			continue
+		}
+		for _, section := range report.Sections {
+			for _, show := range section.Shows {
+				if q.Matches(show) {
+					matches = append(matches, Match{Week: w, Section: section.Title, Show: show})
+				}
+			}
+		}
+	}
+	return matches, nil
+}
+
+// Matches checks whether the show matches all of the fields of the query, ignoring the case
+func (q Query) Matches(show tvshow.TvShow) bool {
+	if q.Title != "" && !strings.Contains(strings.ToLower(show.Title), strings.ToLower(strings.TrimSpace(q.Title))) {
+		return false
+	}
+	if q.Genre != "" && !containsFold(show.Genres, q.Genre) {
+		return false
+	}
+	if q.Streamer != "" {
+		names := []string{show.Network}
+		for _, p := range show.StreamingOptions {
+			names = append(names, string(p.Name))
+		}
+		if !containsFold(names, q.Streamer) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty is true if the query has no fields
+func (q Query) IsEmpty() bool {
+	return q.Title == "" && q.Genre == "" && q.Streamer == ""
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), strings.TrimSpace(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+func weekKey(title string, date time.Time) string {
+	return fmt.Sprintf("%s-%s", title, date.Format(yyyyMMdd))
+}
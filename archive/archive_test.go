@@ -0,0 +1,114 @@
+package archive
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+func testArchive(t *testing.T) Archive {
+	outputPath := t.TempDir()
+	a := NewArchive(outputPath)
+
+	hbo := []streamer.Provider{{Name: "HBO Max", Type: streamer.Streaming}}
+	netflix := []streamer.Provider{{Name: streamer.Netflix, Type: streamer.Streaming}}
+	require.NoError(t, a.Save(view.Report{Title: "tv", StartDate: "June 1", EndDate: "June 8", Sections: []view.Section{
+		{Id: view.SectionReturning, Title: "Returning Series", Shows: []tvshow.TvShow{{Title: "Game of Thrones", Genres: []string{"Drama"}, StreamingOptions: hbo, Network: "HBO"}}},
+		{Id: view.SectionNew, Title: "New Series", Shows: []tvshow.TvShow{{Title: "#BlackAF", Genres: []string{"Comedy"}, StreamingOptions: netflix}}},
+	}}, time.Date(2020, 6, 8, 14, 0, 0, 0, time.UTC)))
+	require.NoError(t, a.Save(view.Report{Title: "tv", StartDate: "June 8", EndDate: "June 15", Sections: []view.Section{
+		{Id: view.SectionNew, Title: "New Series", Shows: []tvshow.TvShow{{Title: "Élite", Genres: []string{"Drama", "Thriller"}, StreamingOptions: netflix}}},
+	}}, time.Date(2020, 6, 15, 14, 0, 0, 0, time.UTC)))
+
+	for _, name := range []string{"tv-20200608.html", "tv-20200601.html", "tv-20200615.md", "tv.atom", "lastprocessed.dat"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(outputPath, name), []byte("report"), 0644))
+	}
+	return a
+}
+
+func Test_Weeks(t *testing.T) {
+	//given
+	a := testArchive(t)
+
+	//when
+	weeks, err := a.Weeks()
+
+	//then
+	require.NoError(t, err)
+	require.Equal(t, 3, len(weeks))
+	assert.Equal(t, Week{Key: "tv-20200615", Title: "tv", Date: time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC), Files: map[string]string{"md": "tv-20200615.md"}, Archived: true}, weeks[0])
+	assert.Equal(t, Week{Key: "tv-20200608", Title: "tv", Date: time.Date(2020, 6, 8, 0, 0, 0, 0, time.UTC), Files: map[string]string{"html": "tv-20200608.html"}, Archived: true}, weeks[1])
+	assert.Equal(t, Week{Key: "tv-20200601", Title: "tv", Date: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), Files: map[string]string{"html": "tv-20200601.html"}}, weeks[2])
+}
+
+func Test_Load(t *testing.T) {
+	//given
+	a := testArchive(t)
+	week, err := a.Week("tv-20200608")
+	require.NoError(t, err)
+
+	//when
+	report, err := a.Load(week)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "June 1", report.StartDate)
+	assert.Equal(t, 2, len(report.AllShows()))
+
+	_, err = a.Week("tv-20200101")
+	assert.Error(t, err)
+}
+
+func Test_Search(t *testing.T) {
+	a := testArchive(t)
+
+	testcases := map[string]struct {
+		Query    Query
+		Expected []string
+	}{
+		"Title": {
+			Query:    Query{Title: "thrones"},
+			Expected: []string{"Game of Thrones"},
+		},
+		"Genre, newest first": {
+			Query:    Query{Genre: "drama"},
+			Expected: []string{"Élite", "Game of Thrones"},
+		},
+		"Streamer": {
+			Query:    Query{Streamer: "netflix"},
+			Expected: []string{"Élite", "#BlackAF"},
+		},
+		"Network": {
+			Query:    Query{Streamer: "HBO"},
+			Expected: []string{"Game of Thrones"},
+		},
+		"All of the fields": {
+			Query:    Query{Genre: "Drama", Streamer: "Netflix"},
+			Expected: []string{"Élite"},
+		},
+		"Nothing": {
+			Query:    Query{Title: "Sweet Magnolias"},
+			Expected: []string{},
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//when
+		matches, err := a.Search(testdata.Query)
+
+		//then
+		require.NoError(t, err, testcase)
+		titles := make([]string, 0, len(matches))
+		for _, m := range matches {
+			titles = append(titles, m.Show.Title)
+		}
+		assert.Equal(t, testdata.Expected, titles, testcase)
+	}
+}
@@ -0,0 +1,47 @@
+package availability
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/streamer"
+)
+
+// Resolver looks up which streaming services a show is available on in a region. The bool
+// is false when there's no data for the show, in which case the scraped streamers are kept.
+type Resolver interface {
+	Lookup(imdbId string, region string) ([]streamer.Streamer, bool, error)
+}
+
+// NewResolver returns the resolver for the configured data source: a local dataset file or a
+// provider's HTTP endpoint. Without either, nothing is ever found.
+func NewResolver(conf config.Availability) (Resolver, error) {
+	if conf.File != "" && conf.Url != "" {
+		return nil, fmt.Errorf("only one of availability file and url may be set")
+	}
+
+	if conf.File != "" {
+		switch strings.ToLower(filepath.Ext(conf.File)) {
+		case ".json":
+			return NewJsonFileResolver(conf.File)
+		case ".csv":
+			return NewCsvFileResolver(conf.File)
+		default:
+			return nil, fmt.Errorf("unsupported availability file format: %s", conf.File)
+		}
+	}
+
+	if conf.Url != "" {
+		return NewHttpResolver(conf.Url), nil
+	}
+
+	return noopResolver{}, nil
+}
+
+type noopResolver struct{}
+
+func (r noopResolver) Lookup(imdbId string, region string) ([]streamer.Streamer, bool, error) {
+	return nil, false, nil
+}
@@ -0,0 +1,100 @@
+package availability
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/streamer"
+)
+
+func Test_FileResolver(t *testing.T) {
+	for _, file := range []string{"testdata/availability.json", "testdata/availability.csv"} {
+		//given
+		resolver, err := NewResolver(config.Availability{File: file})
+		require.NoError(t, err, "There was an error loading the dataset", file)
+
+		testcases := map[string]struct {
+			ImdbId     string
+			Region     string
+			Expected   []streamer.Streamer
+			ExpectedOk bool
+		}{
+			"Several streamers": {
+				ImdbId:     "tt0944947",
+				Region:     "DE",
+				Expected:   []streamer.Streamer{"Sky", "WOW"},
+				ExpectedOk: true,
+			},
+			"Region is case insensitive": {
+				ImdbId:     "tt10240086",
+				Region:     "us",
+				Expected:   []streamer.Streamer{"Netflix"},
+				ExpectedOk: true,
+			},
+			"Unknown region": {
+				ImdbId:     "tt0944947",
+				Region:     "FR",
+				ExpectedOk: false,
+			},
+			"Unknown show": {
+				ImdbId:     "tt7134908",
+				Region:     "DE",
+				ExpectedOk: false,
+			},
+		}
+
+		for testcase, testdata := range testcases {
+			//when
+			streamers, ok, err := resolver.Lookup(testdata.ImdbId, testdata.Region)
+
+			//then
+			require.NoError(t, err, testcase)
+			assert.Equal(t, testdata.ExpectedOk, ok, file, testcase)
+			assert.Equal(t, testdata.Expected, streamers, file, testcase)
+		}
+	}
+}
+
+func Test_HttpResolver(t *testing.T) {
+	//given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/availability/tt0944947" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(rw).Encode(map[string][]string{"streamers": {req.URL.Query().Get("region") + " Streamer"}})
+	}))
+	defer server.Close()
+
+	resolver, err := NewResolver(config.Availability{Url: server.URL + "/"})
+	require.NoError(t, err)
+
+	//when
+	streamers, ok, err := resolver.Lookup("tt0944947", "DE")
+	_, unknownOk, unknownErr := resolver.Lookup("tt7134908", "DE")
+
+	//then
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []streamer.Streamer{"DE Streamer"}, streamers)
+	require.NoError(t, unknownErr)
+	assert.False(t, unknownOk)
+}
+
+func Test_NewResolver_Invalid(t *testing.T) {
+	testcases := map[string]config.Availability{
+		"Both file and url":  {File: "testdata/availability.json", Url: "http://localhost"},
+		"Unsupported format": {File: "testdata/availability.xml"},
+		"Missing file":       {File: "testdata/does-not-exist.json"},
+	}
+
+	for testcase, conf := range testcases {
+		_, err := NewResolver(conf)
+		assert.Error(t, err, testcase)
+	}
+}
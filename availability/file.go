@@ -0,0 +1,93 @@
+package availability
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+// FileResolver looks up the availability in a locally maintained dataset
+type FileResolver struct {
+	dataset map[string]map[string][]streamer.Streamer //imdb id -> region -> streamers
+}
+
+// NewJsonFileResolver loads a dataset in the format {"tt0944947": {"DE": ["Sky"], "US": ["HBO Max"]}}
+func NewJsonFileResolver(path string) (FileResolver, error) {
+	r := FileResolver{dataset: make(map[string]map[string][]streamer.Streamer)}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return r, err
+	}
+
+	raw := make(map[string]map[string][]streamer.Streamer)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return r, fmt.Errorf("%s: %w", path, err)
+	}
+	for id, regions := range raw {
+		for region, streamers := range regions {
+			r.add(id, region, streamers...)
+		}
+	}
+
+	return r, nil
+}
+
+// NewCsvFileResolver loads a dataset with the columns imdb_id,region,streamer and a header row
+func NewCsvFileResolver(path string) (FileResolver, error) {
+	r := FileResolver{dataset: make(map[string]map[string][]streamer.Streamer)}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return r, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = 3
+	reader.Comment = '#'
+
+	if _, err := reader.Read(); err != nil {
+		return r, fmt.Errorf("%s: error reading header: %w", path, err)
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return r, fmt.Errorf("%s: %w", path, err)
+		}
+		r.add(record[0], record[1], streamer.Streamer(strings.TrimSpace(record[2])))
+	}
+
+	return r, nil
+}
+
+func (r FileResolver) add(id string, region string, streamers ...streamer.Streamer) {
+	id = tvshow.ParseImdbId(id)
+	if id == "" {
+		return
+	}
+	if _, ok := r.dataset[id]; !ok {
+		r.dataset[id] = make(map[string][]streamer.Streamer)
+	}
+	region = strings.ToUpper(strings.TrimSpace(region))
+	r.dataset[id][region] = append(r.dataset[id][region], streamers...)
+}
+
+func (r FileResolver) Lookup(imdbId string, region string) ([]streamer.Streamer, bool, error) {
+	regions, ok := r.dataset[imdbId]
+	if !ok {
+		return nil, false, nil
+	}
+	streamers, ok := regions[strings.ToUpper(region)]
+	return streamers, ok, nil
+}
@@ -0,0 +1,54 @@
+package availability
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ynori7/tvshows/streamer"
+)
+
+// HttpResolver asks an availability provider. It requests {baseUrl}/availability/{imdbId}?region={region}
+// and expects a response like {"streamers": ["Netflix"]}, or a 404 if the show is unknown.
+type HttpResolver struct {
+	httpClient *http.Client
+	baseUrl    string
+}
+
+type httpResponse struct {
+	Streamers []streamer.Streamer `json:"streamers"`
+}
+
+func NewHttpResolver(baseUrl string) HttpResolver {
+	return HttpResolver{
+		httpClient: &http.Client{Timeout: 30 * time.Second}, //a hung provider shouldn't hold up the run
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+	}
+}
+
+func (r HttpResolver) Lookup(imdbId string, region string) ([]streamer.Streamer, bool, error) {
+	params := url.Values{}
+	params.Add("region", region)
+
+	res, err := r.httpClient.Get(fmt.Sprintf("%s/availability/%s?%s", r.baseUrl, url.PathEscape(imdbId), params.Encode()))
+	if err != nil {
+		return nil, false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("status code error: %d %s", res.StatusCode, res.Status)
+	}
+
+	var body httpResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, false, err
+	}
+	return body.Streamers, true, nil
+}
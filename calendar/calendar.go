@@ -0,0 +1,241 @@
+package calendar
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+const (
+	defaultMaxAgeDays = 90
+	icsDate           = "20060102"
+	icsTimestamp      = "20060102T150405Z"
+)
+
+// event is a VEVENT block of the calendar
+type event struct {
+	uid   string
+	start time.Time
+	raw   string //the rendered block, so that the events of previous runs can be kept as they are
+}
+
+// Calendar maintains an iCalendar file in the output directory with an all-day event for
+// each reported show on its premiere date. Events from previous runs are kept until they're
+// older than the max age.
+type Calendar struct {
+	conf       config.Config
+	outputPath string
+	now        time.Time
+}
+
+func NewCalendar(conf config.Config, outputPath string) Calendar {
+	return Calendar{
+		conf:       conf,
+		outputPath: outputPath,
+		now:        time.Now(),
+	}
+}
+
+func (c Calendar) File() string {
+	return filepath.Join(c.outputPath, fmt.Sprintf("%s.ics", c.conf.Title))
+}
+
+// Update adds the shows in the report to the calendar file
+func (c Calendar) Update(report view.Report) error {
+	events, err := c.loadEvents()
+	if err != nil {
+		return err
+	}
+
+	byUid := make(map[string]event, len(events))
+	for _, e := range events {
+		byUid[e.uid] = e
+	}
+	for _, show := range report.AllShows() {
+		if show.PremiereDate.IsZero() {
+			continue
+		}
+		e := c.newEvent(show)
+		byUid[e.uid] = e //the newest details win
+	}
+
+	//Drop the old events
+	oldest := c.now.AddDate(0, 0, -c.maxAgeDays())
+	events = make([]event, 0, len(byUid))
+	for _, e := range byUid {
+		if !e.start.Before(oldest) {
+			events = append(events, e)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].start.Equal(events[j].start) {
+			return events[i].start.Before(events[j].start)
+		}
+		return events[i].uid < events[j].uid
+	})
+
+	return ioutil.WriteFile(c.File(), []byte(c.render(events)), 0644)
+}
+
+func (c Calendar) maxAgeDays() int {
+	if c.conf.Calendar.MaxAgeDays <= 0 {
+		return defaultMaxAgeDays
+	}
+	return c.conf.Calendar.MaxAgeDays
+}
+
+func (c Calendar) newEvent(show tvshow.TvShow) event {
+	id := show.ImdbId
+	if id == "" {
+		id = strings.ToLower(strings.Join(strings.Fields(show.Title), "-"))
+	}
+	uid := fmt.Sprintf("%s-%s@tvshows", id, show.PremiereDate.Format(icsDate))
+
+	summary := show.Title
+	if show.IsNewSeries {
+		summary += " (new series)"
+	}
+
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + uid,
+		"DTSTAMP:" + c.now.UTC().Format(icsTimestamp),
+		"DTSTART;VALUE=DATE:" + show.PremiereDate.Format(icsDate),
+		"DTEND;VALUE=DATE:" + show.PremiereDate.AddDate(0, 0, 1).Format(icsDate),
+		"SUMMARY:" + escape(summary),
+		"DESCRIPTION:" + escape(description(show)),
+		"TRANSP:TRANSPARENT",
+	}
+	if show.Link != "" {
+		lines = append(lines, "URL:"+show.Link)
+	}
+	lines = append(lines, "END:VEVENT")
+
+	folded := make([]string, 0, len(lines))
+	for _, l := range lines {
+		folded = append(folded, fold(l))
+	}
+
+	return event{
+		uid:   uid,
+		start: show.PremiereDate,
+		raw:   strings.Join(folded, "\r\n"),
+	}
+}
+
+func description(show tvshow.TvShow) string {
+	parts := []string{
+		fmt.Sprintf("Rated %s/10 by %d users, score %d/100", show.Rating.AverageRating, show.Rating.RatingCount, show.Score),
+	}
+	if len(show.Genres) > 0 {
+		parts = append(parts, strings.Join(show.Genres, ", "))
+	}
+	if show.Description != "" {
+		parts = append(parts, show.Description)
+	}
+
+	streaming := make([]string, 0)
+	for _, p := range show.StreamingOptions {
+		if p.Type != streamer.Network {
+			streaming = append(streaming, string(p.Name))
+		}
+	}
+	if len(streaming) > 0 {
+		parts = append(parts, "Available on "+strings.Join(streaming, ", "))
+	}
+	if show.Link != "" {
+		parts = append(parts, show.Link)
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+func (c Calendar) render(events []event) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//ynori7//tvshows//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		fold("X-WR-CALNAME:" + escape(fmt.Sprintf("%s premieres", c.conf.Title))),
+	}
+	for _, e := range events {
+		lines = append(lines, e.raw)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// loadEvents reads the events from the existing calendar file
+func (c Calendar) loadEvents() ([]event, error) {
+	data, err := ioutil.ReadFile(c.File())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]event, 0)
+	var current []string
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		switch {
+		case line == "BEGIN:VEVENT":
+			current = []string{line}
+		case line == "END:VEVENT" && current != nil:
+			current = append(current, line)
+			e := event{raw: strings.Join(current, "\r\n")}
+			for _, l := range current {
+				if strings.HasPrefix(l, "UID:") {
+					e.uid = strings.TrimPrefix(l, "UID:")
+				}
+				if strings.HasPrefix(l, "DTSTART;VALUE=DATE:") {
+					e.start, _ = time.Parse(icsDate, strings.TrimPrefix(l, "DTSTART;VALUE=DATE:"))
+				}
+			}
+			if e.uid != "" {
+				events = append(events, e)
+			}
+			current = nil
+		case current != nil:
+			current = append(current, line)
+		}
+	}
+
+	return events, nil
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// escape escapes the text value as described in RFC 5545 section 3.3.11
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// fold breaks lines longer than 75 octets, continuing them with a space
+func fold(line string) string {
+	const limit = 75
+
+	var b strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > limit {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	return b.String()
+}
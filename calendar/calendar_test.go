@@ -0,0 +1,72 @@
+package calendar
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+func Test_Update(t *testing.T) {
+	//given
+	conf := config.Config{Title: "my-conf", Calendar: config.Calendar{Enabled: true, MaxAgeDays: 30}}
+	c := NewCalendar(conf, t.TempDir())
+	c.now = time.Date(2020, 6, 15, 14, 0, 0, 0, time.UTC)
+
+	//an old event which should be dropped
+	require.NoError(t, c.Update(view.Report{
+		Sections: []view.Section{{Shows: []tvshow.TvShow{{Title: "Old Show", ImdbId: "tt0000001", PremiereDate: time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)}}}},
+	}))
+	//last week's event which should be kept
+	require.NoError(t, c.Update(view.Report{
+		Sections: []view.Section{{Shows: []tvshow.TvShow{{Title: "#BlackAF", ImdbId: "tt10311562", IsNewSeries: true, PremiereDate: time.Date(2020, 6, 4, 0, 0, 0, 0, time.UTC)}}}},
+	}))
+
+	//when
+	err := c.Update(view.Report{
+		Sections: []view.Section{{Shows: []tvshow.TvShow{
+			{
+				Title:            "Game of Thrones",
+				ImdbId:           "tt0944947",
+				Link:             "https://www.imdb.com/title/tt0944947/",
+				Genres:           []string{"Action", "Adventure", "Drama"},
+				Rating:           tvshow.Rating{AverageRating: "9.2", RatingCount: 1865597},
+				Score:            100,
+				Description:      "Nine noble families fight for control over the lands of Westeros; an ancient enemy returns.",
+				StreamingOptions: []streamer.Provider{{Name: "HBO", Type: streamer.Network}, {Name: "HBO Max", Type: streamer.Streaming}},
+				PremiereDate:     time.Date(2020, 6, 11, 0, 0, 0, 0, time.UTC),
+			},
+			{Title: "No Date"},
+		}}},
+	})
+
+	//then
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(c.File())
+	require.NoError(t, err)
+	ics := string(data)
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT"))
+	assert.NotContains(t, ics, "Old Show")
+	assert.Contains(t, ics, "SUMMARY:#BlackAF (new series)")
+	assert.Contains(t, ics, "UID:tt0944947-20200611@tvshows")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20200611\r\nDTEND;VALUE=DATE:20200612")
+	assert.Contains(t, ics, "URL:https://www.imdb.com/title/tt0944947/")
+	assert.Less(t, strings.Index(ics, "#BlackAF"), strings.Index(ics, "Game of Thrones"), "The events should be sorted by date")
+
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	assert.Contains(t, unfolded, `DESCRIPTION:Rated 9.2/10 by 1865597 users\, score 100/100\n\nAction\, Adventure\, Drama`)
+	assert.Contains(t, unfolded, `Westeros\; an ancient enemy returns.\n\nAvailable on HBO Max\n\nhttps://www.imdb.com/title/tt0944947/`)
+	for _, line := range strings.Split(ics, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, "Lines should be folded")
+	}
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/application"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/logging"
+)
+
+// command is a subcommand, which defines its flags on the flag set, parses the arguments after its name and
+// returns the exit code
+type command struct {
+	usage       string
+	description string
+	run         func(e env, fs *flag.FlagSet, args []string) int
+}
+
+// env is where the commands write to
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+var commands = map[string]command{
+	"premieres": {usage: "premieres [flags]", description: "generate the report of the new premieres", run: premieresCommand},
+	"lookup":    {usage: "lookup [flags] <title>", description: "look up a title and show whether it would make it into the report, and why", run: lookupCommand},
+	"show":      {usage: "show [flags] <imdb-id>", description: "show the IMDB details and score of a series", run: showCommand},
+	"config":    {usage: "config validate [flags]", description: "check the config and the files it refers to", run: configCommand},
+	"web":       {usage: "web [flags]", description: "serve the UI for the past reports", run: webCommand},
+	"api":       {usage: "api [flags]", description: "serve the JSON API over the premieres pipeline", run: apiCommand},
+}
+
+// Run runs the command named by the first argument and returns the exit code
+func Run(args []string, stdout io.Writer, stderr io.Writer) int {
+	e := env{stdout: stdout, stderr: stderr}
+	if len(args) == 0 {
+		e.usage()
+		return application.ExitConfig
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		e.usage()
+		return application.ExitOk
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command: %s\n\n", args[0])
+		e.usage()
+		return application.ExitConfig
+	}
+	return cmd.run(e, e.flagSet(args[0], cmd.usage), args[1:])
+}
+
+func (e env) usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(e.stderr, "Usage: tvshows <command> [flags]")
+	fmt.Fprintln(e.stderr, "\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(e.stderr, "  %-26s %s\n", commands[name].usage, commands[name].description)
+	}
+	fmt.Fprintln(e.stderr, "\nRun tvshows <command> -h for the flags of a command.")
+}
+
+// flagSet creates the flag set of a command, which prints its errors and usage to stderr
+func (e env) flagSet(name string, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: tvshows %s\n\nFlags:\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the flags, and returns the exit code if the command shouldn't go on
+func parse(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return application.ExitOk, false
+		}
+		return application.ExitConfig, false
+	}
+	return 0, true
+}
+
+// setup loads the config and builds the logger, as the flags and then the config say. Until the config is loaded,
+// the failures are logged as the flags say. If the config is optional, an empty path means the defaults. When it
+// fails, it returns the exit code.
+func (e env) setup(cliConf config.CliConfig, path string, optional bool) (config.Config, log.FieldLogger, int, bool) {
+	var conf config.Config
+	logger, err := logging.New(cliConf.Logging(conf.Logging), e.stderr)
+	if err != nil {
+		fallback, _ := logging.New(config.Logging{}, e.stderr) //the defaults are always valid
+		return conf, nil, fail(fallback, err, "Invalid logging flags"), false
+	}
+
+	if path != "" || !optional {
+		if conf, err = loadConfig(path); err != nil {
+			return conf, nil, fail(logger, err, "Error loading config"), false
+		}
+	}
+
+	confLogger, err := logging.New(cliConf.Logging(conf.Logging), e.stderr)
+	if err != nil {
+		return conf, nil, fail(logger, err, "Invalid logging config"), false
+	}
+	return conf, confLogger, application.ExitOk, true
+}
+
+// loadConfig reads and parses the config file, applies the environment variables and validates the options
+func loadConfig(path string) (config.Config, error) {
+	var conf config.Config
+	if path == "" {
+		return conf, fmt.Errorf("you must specify the path to the config file")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return conf, fmt.Errorf("error reading config file: %w", err)
+	}
+	if err := conf.ParseStrict(data); err != nil {
+		return conf, fmt.Errorf("error parsing config: %w", err)
+	}
+	if err := conf.ApplyEnv(os.LookupEnv); err != nil {
+		return conf, fmt.Errorf("error applying the environment: %w", err)
+	}
+	if err := conf.Validate(); err != nil {
+		return conf, fmt.Errorf("invalid config: %w", err)
+	}
+	return conf, nil
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ynori7/tvshows/application"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+func Test_Run(t *testing.T) {
+	testcases := map[string]struct {
+		Args           []string
+		ExpectedCode   int
+		ExpectedStdout []string
+		ExpectedStderr []string
+	}{
+		"No command": {
+			Args:           []string{},
+			ExpectedCode:   application.ExitConfig,
+			ExpectedStderr: []string{"Usage: tvshows <command>", "lookup [flags] <title>"},
+		},
+		"Help": {
+			Args:           []string{"help"},
+			ExpectedCode:   application.ExitOk,
+			ExpectedStderr: []string{"Usage: tvshows <command>"},
+		},
+		"Unknown command": {
+			Args:           []string{"recommend"},
+			ExpectedCode:   application.ExitConfig,
+			ExpectedStderr: []string{"unknown command: recommend"},
+		},
+		"Help of a command": {
+			Args:           []string{"lookup", "-h"},
+			ExpectedCode:   application.ExitOk,
+			ExpectedStderr: []string{"Usage: tvshows lookup [flags] <title>", "-new"},
+		},
+		"Lookup without a title": {
+			Args:           []string{"lookup", "--config", "testdata/valid.yaml"},
+			ExpectedCode:   application.ExitConfig,
+			ExpectedStderr: []string{"Usage: tvshows lookup"},
+		},
+		"Show with an invalid ID": {
+			Args:           []string{"show", "game-of-thrones"},
+			ExpectedCode:   application.ExitConfig,
+			ExpectedStderr: []string{"is not an IMDB ID"},
+		},
+		"Missing config is logged as the flags say": {
+			Args:           []string{"lookup", "--log-format", "json", "--config", "testdata/missing.yaml", "Sweet Magnolias"},
+			ExpectedCode:   application.ExitConfig,
+			ExpectedStderr: []string{`"msg":"Error loading config"`},
+		},
+		"Invalid logging flags": {
+			Args:           []string{"show", "--log-level", "loud", "tt0944947"},
+			ExpectedCode:   application.ExitConfig,
+			ExpectedStderr: []string{"Invalid logging flags", "not a valid logrus Level"},
+		},
+		"Config without the subcommand": {
+			Args:           []string{"config", "testdata/valid.yaml"},
+			ExpectedCode:   application.ExitConfig,
+			ExpectedStderr: []string{"Usage: tvshows config validate"},
+		},
+		"Valid config": {
+			Args:           []string{"config", "validate", "--config", "testdata/valid.yaml"},
+			ExpectedCode:   application.ExitOk,
+			ExpectedStdout: []string{"testdata/valid.yaml is valid"},
+		},
+		"Valid config as an argument": {
+			Args:           []string{"config", "validate", "testdata/valid.yaml"},
+			ExpectedCode:   application.ExitOk,
+			ExpectedStdout: []string{"testdata/valid.yaml is valid"},
+		},
+		"Invalid config": {
+			Args:         []string{"config", "validate", "testdata/invalid.yaml"},
+			ExpectedCode: application.ExitConfig,
+			ExpectedStdout: []string{
+				"has 4 problem(s)",
+				`report.sort_by must be one of [score relevance date], not "rating"`,
+				"report: unsupported theme: sepia",
+				"daemon.schedule:",
+				"watchlist:",
+			},
+		},
+		"Config with a typo": {
+			Args:           []string{"config", "validate", "testdata/typo.yaml"},
+			ExpectedCode:   application.ExitConfig,
+			ExpectedStdout: []string{"has 1 problem(s)", "field reprot not found"},
+		},
+		"Missing config": {
+			Args:           []string{"config", "validate", "testdata/missing.yaml"},
+			ExpectedCode:   application.ExitConfig,
+			ExpectedStdout: []string{"no such file or directory"},
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		var stdout, stderr bytes.Buffer
+
+		//when
+		code := Run(testdata.Args, &stdout, &stderr)
+
+		//then
+		assert.Equal(t, testdata.ExpectedCode, code, testcase)
+		for _, expected := range testdata.ExpectedStdout {
+			assert.Contains(t, stdout.String(), expected, testcase)
+		}
+		for _, expected := range testdata.ExpectedStderr {
+			assert.Contains(t, stderr.String(), expected, testcase)
+		}
+	}
+}
+
+func Test_Run_ConfigFromEnv(t *testing.T) {
+	//given
+	t.Setenv("TVSHOWS_REPORT_SORT_BY", "rating")
+	t.Setenv("TVSHOWS_POSTERS_WIDTH", "wide")
+	var stdout, stderr bytes.Buffer
+
+	//when
+	code := Run([]string{"config", "validate", "testdata/valid.yaml"}, &stdout, &stderr)
+
+	//then
+	assert.Equal(t, application.ExitConfig, code)
+	assert.Contains(t, stdout.String(), "has 2 problem(s)")
+	assert.Contains(t, stdout.String(), "TVSHOWS_POSTERS_WIDTH: invalid number: wide")
+	assert.Contains(t, stdout.String(), `report.sort_by must be one of [score relevance date], not "rating"`)
+}
+
+func Test_loadConfig(t *testing.T) {
+	testcases := map[string]struct {
+		Path          string
+		ExpectedError string
+	}{
+		"Valid config": {
+			Path: "testdata/valid.yaml",
+		},
+		"Invalid option": {
+			Path:          "testdata/invalid.yaml",
+			ExpectedError: `report.sort_by must be one of [score relevance date], not "rating"`,
+		},
+		"Unknown key": {
+			Path:          "testdata/typo.yaml",
+			ExpectedError: "field reprot not found",
+		},
+		"No path": {
+			ExpectedError: "you must specify the path to the config file",
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//when
+		_, err := loadConfig(testdata.Path)
+
+		//then
+		if testdata.ExpectedError == "" {
+			assert.NoError(t, err, testcase)
+			continue
+		}
+		assert.ErrorContains(t, err, testdata.ExpectedError, testcase)
+	}
+}
+
+func Test_PrintLookup(t *testing.T) {
+	//given
+	show := tvshow.TvShow{
+		Title:          "Game of Thrones",
+		Link:           "https://www.imdb.com/title/tt0944947/",
+		Genres:         []string{"Action", "Drama"},
+		Rating:         tvshow.Rating{AverageRating: "9.2", RatingCount: 2300000},
+		Score:          100,
+		RelevanceScore: 63,
+		PersonalRating: 4,
+	}
+	var out bytes.Buffer
+
+	//when
+	printShow(&out, show)
+	printResult(&out, jsonLookup{Result: "suppressed_by_ratings", Reason: "suppressed by personal ratings: we rated it 4"})
+
+	//then
+	expected := `Title:            Game of Thrones
+IMDB:             https://www.imdb.com/title/tt0944947/
+Genres:           Action, Drama
+Rating:           9.2 (2300000 ratings)
+Score:            100
+Relevance:        63
+Personal rating:  4
+Result:           suppressed by personal ratings: we rated it 4 (suppressed_by_ratings)
+`
+	assert.Equal(t, expected, out.String())
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ynori7/tvshows/application"
+	"github.com/ynori7/tvshows/availability"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/logging"
+	"github.com/ynori7/tvshows/ratings"
+	"github.com/ynori7/tvshows/schedule"
+	"github.com/ynori7/tvshows/view"
+	"github.com/ynori7/tvshows/watchlist"
+)
+
+// configCommand checks the config with the environment variables applied, and whether the files it refers to can be loaded, without fetching anything
+func configCommand(e env, fs *flag.FlagSet, args []string) int {
+	if len(args) == 0 || args[0] != "validate" {
+		fs.Usage()
+		return application.ExitConfig
+	}
+	configFile := fs.String("config", "", "the path to the configuration yaml")
+	if code, ok := parse(fs, args[1:]); !ok {
+		return code
+	}
+	if *configFile == "" && fs.NArg() == 1 {
+		*configFile = fs.Arg(0)
+	}
+	if *configFile == "" {
+		fs.Usage()
+		return application.ExitConfig
+	}
+
+	problems := validateConfig(*configFile)
+	if len(problems) == 0 {
+		fmt.Fprintf(e.stdout, "%s is valid\n", *configFile)
+		return application.ExitOk
+	}
+
+	fmt.Fprintf(e.stdout, "%s has %d problem(s):\n", *configFile, len(problems))
+	for _, p := range problems {
+		fmt.Fprintf(e.stdout, "  - %s\n", p)
+	}
+	return application.ExitConfig
+}
+
+// validateConfig returns the problems with the config, each of which would fail a run
+func validateConfig(path string) []error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return []error{err}
+	}
+
+	var conf config.Config
+	if err := conf.ParseStrict(data); err != nil {
+		return []error{err}
+	}
+
+	problems := make([]error, 0)
+	problems = append(problems, unjoin(conf.ApplyEnv(os.LookupEnv))...)
+	problems = append(problems, unjoin(conf.Validate())...)
+	if _, err := logging.New(config.Logging{Level: conf.Logging.Level}, ioutil.Discard); err != nil { //the format is validated above
+		problems = append(problems, fmt.Errorf("logging: %w", err))
+	}
+	if _, err := view.NewTemplates(conf.Report.TemplatesDir, conf.Report.Theme); err != nil {
+		problems = append(problems, fmt.Errorf("report: %w", err))
+	}
+	if conf.Daemon.Schedule != "" {
+		if _, err := schedule.Parse(conf.Daemon.Schedule); err != nil {
+			problems = append(problems, fmt.Errorf("daemon.schedule: %w", err))
+		}
+	}
+	if _, err := watchlist.Load(conf.Watchlist.Files); err != nil {
+		problems = append(problems, fmt.Errorf("watchlist: %w", err))
+	}
+	if _, err := ratings.Load(conf.Ratings.Files, conf.Ratings.DroppedFiles); err != nil {
+		problems = append(problems, fmt.Errorf("ratings: %w", err))
+	}
+	if _, err := availability.NewResolver(conf.Availability); err != nil {
+		problems = append(problems, fmt.Errorf("availability: %w", err))
+	}
+	return problems
+}
+
+// unjoin splits the errors which were joined with errors.Join, to list them one by one
+func unjoin(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/application"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/enrich"
+	"github.com/ynori7/tvshows/metrics"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+// The outcome of a lookup which made it into the results
+const resultIncluded = "included"
+
+type jsonLookup struct {
+	Result   string           `json:"result"` //included or the code of the rejection
+	Reason   string           `json:"reason,omitempty"`
+	MinScore int              `json:"min_score"`
+	Show     *view.JsonTvShow `json:"show,omitempty"` //if it was found
+}
+
+// lookupCommand looks up the title like a premiere in the report and shows whether it'd be included and why
+func lookupCommand(e env, fs *flag.FlagSet, args []string) int {
+	configFile := fs.String("config", "", "the path to the configuration yaml")
+	isNew := fs.Bool("new", false, "look it up as a new series rather than a returning one")
+	asJson := fs.Bool("json", false, "print the result as JSON")
+	cliConf := new(config.CliConfig)
+	config.RegisterLoggingFlags(fs, cliConf)
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return application.ExitConfig
+	}
+	title := fs.Arg(0)
+
+	conf, logger, code, ok := e.setup(*cliConf, *configFile, false)
+	if !ok {
+		return code
+	}
+
+	result, err := application.LookupTitle(conf, title, *isNew, logger, metrics.New())
+	if err != nil {
+		return fail(logger, err, "Error loading the lists")
+	}
+
+	lookup := jsonLookup{Result: resultIncluded, MinScore: enrich.MinScore(*isNew)}
+	var show *tvshow.TvShow
+	switch {
+	case len(result.Series) > 0:
+		show = &result.Series[0]
+	case len(result.Rejected) > 0:
+		lookup.Result = result.Rejected[0].Code
+		lookup.Reason = result.Rejected[0].Reason
+		show = result.Rejected[0].Series
+	}
+	if show != nil {
+		lookup.Show = &view.ToJsonTvShows([]tvshow.TvShow{*show})[0]
+	}
+
+	if *asJson {
+		if err := printJson(e.stdout, lookup); err != nil {
+			return fail(logger, err, "Error printing the result")
+		}
+	} else {
+		if show != nil {
+			printShow(e.stdout, *show)
+			fmt.Fprintf(e.stdout, "%-17s %d\n", "Needed score:", lookup.MinScore)
+		}
+		printResult(e.stdout, lookup)
+	}
+
+	if lookup.Result == enrich.CodeLookupFailed {
+		return application.ExitEnrichment
+	}
+	return application.ExitOk
+}
+
+// showCommand prints the IMDB details of the series
+func showCommand(e env, fs *flag.FlagSet, args []string) int {
+	configFile := fs.String("config", "", "the path to the configuration yaml, for the logging (optional)")
+	asJson := fs.Bool("json", false, "print the show as JSON")
+	cliConf := new(config.CliConfig)
+	config.RegisterLoggingFlags(fs, cliConf)
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return application.ExitConfig
+	}
+
+	conf, logger, code, ok := e.setup(*cliConf, *configFile, true)
+	if !ok {
+		return code
+	}
+
+	imdbId := tvshow.ParseImdbId(fs.Arg(0))
+	if imdbId == "" {
+		return fail(logger, fmt.Errorf("%s is not an IMDB ID, e.g. tt0944947", fs.Arg(0)), "Invalid IMDB ID")
+	}
+
+	client := tvshow.NewImdbClient(conf, logger, metrics.New())
+	show, err := client.GetTvShowData(client.BuildTitleLink(imdbId))
+	if err != nil {
+		logger.WithFields(log.Fields{"Logger": "main", "error": err}).Error("Error looking up the show")
+		return application.ExitEnrichment
+	}
+
+	if *asJson {
+		if err := printJson(e.stdout, view.ToJsonTvShows([]tvshow.TvShow{*show})[0]); err != nil {
+			return fail(logger, err, "Error printing the show")
+		}
+		return application.ExitOk
+	}
+	printShow(e.stdout, *show)
+	return application.ExitOk
+}
+
+// printShow prints the details which the score and the filters are based on
+func printShow(w io.Writer, show tvshow.TvShow) {
+	row := func(name string, format string, args ...interface{}) {
+		fmt.Fprintf(w, "%-17s %s\n", name+":", fmt.Sprintf(format, args...))
+	}
+	row("Title", "%s", show.Title)
+	row("IMDB", "%s", show.Link)
+	if len(show.Genres) > 0 {
+		row("Genres", "%s", strings.Join(show.Genres, ", "))
+	}
+	if len(show.Creators) > 0 {
+		row("Creators", "%s", strings.Join(show.Creators, ", "))
+	}
+	if show.Rating.AverageRating != "" {
+		row("Rating", "%s (%d ratings)", show.Rating.AverageRating, show.Rating.RatingCount)
+	}
+	row("Score", "%d", show.Score)
+	if show.RelevanceScore > 0 {
+		row("Relevance", "%d", show.RelevanceScore)
+	}
+	if show.OnWatchlist {
+		row("On watchlist", "yes")
+	}
+	if show.PersonalRating > 0 {
+		row("Personal rating", "%d", show.PersonalRating)
+	}
+	if show.Description != "" {
+		row("Description", "%s", show.Description)
+	}
+}
+
+func printResult(w io.Writer, lookup jsonLookup) {
+	switch lookup.Result {
+	case resultIncluded:
+		fmt.Fprintf(w, "%-17s %s\n", "Result:", "included in the report")
+	default:
+		fmt.Fprintf(w, "%-17s %s (%s)\n", "Result:", lookup.Reason, lookup.Result)
+	}
+}
+
+func printJson(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
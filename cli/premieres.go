@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/application"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/daemon"
+	"github.com/ynori7/tvshows/logging"
+	"github.com/ynori7/tvshows/metrics"
+	"github.com/ynori7/tvshows/schedule"
+	"github.com/ynori7/tvshows/view"
+)
+
+// premieresCommand generates the report once, or on the schedule with --serve
+func premieresCommand(e env, fs *flag.FlagSet, args []string) int {
+	startedAt := time.Now()
+	m := metrics.New()
+
+	cliConf := config.RegisterCliFlags(fs)
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	opts := application.NewOptions(*cliConf)
+
+	//Until the config is loaded, the failures are only logged as the flags say
+	var logger log.FieldLogger
+	logger, _ = logging.New(config.Logging{}, e.stderr) //the defaults are always valid
+	failConfig := func(err error, msg string) int {
+		runner := application.NewRunner(config.Config{}, opts, view.Templates{}, logger, m, nil)
+		return runner.Finish(runner.Failed(application.ExitConfig, application.NewSummary(nil, err, startedAt), msg)).ExitCode
+	}
+
+	baseLogger, err := logging.New(cliConf.Logging(config.Logging{}), e.stderr)
+	if err != nil {
+		return failConfig(err, "Invalid logging flags")
+	}
+	logger = baseLogger
+
+	conf, err := loadConfig(cliConf.ConfigFile)
+	if err != nil {
+		return failConfig(err, "Error loading config")
+	}
+
+	confLogger, err := logging.New(cliConf.Logging(conf.Logging), e.stderr)
+	if err != nil {
+		return failConfig(err, "Invalid logging config")
+	}
+	logger = confLogger
+
+	//Load the templates, so that broken custom ones fail before anything is fetched
+	templates, err := view.NewTemplates(conf.Report.TemplatesDir, conf.Report.Theme)
+	if err != nil {
+		return failConfig(err, "Error loading templates")
+	}
+	if _, err := view.NewRenderer(cliConf.Format, templates); err != nil {
+		return failConfig(err, "Invalid format")
+	}
+
+	if cliConf.Serve {
+		s, err := schedule.Parse(conf.Daemon.Schedule)
+		if err != nil {
+			return failConfig(err, "Invalid schedule")
+		}
+		runner := application.NewRunner(conf, opts, templates, logger, m, nil)
+		if err := serve(conf, runner, m, s, logger); err != nil {
+			return failConfig(err, "Error running the daemon")
+		}
+		return application.ExitOk
+	}
+
+	runner := application.NewRunner(conf, opts, templates, logger, m, e.stdout)
+	return runner.Finish(runner.Run()).ExitCode
+}
+
+// serve does the runs on the schedule until SIGINT or SIGTERM. A run in progress is finished first. It fails if the
+// schedule has no next run or the endpoint can't be served.
+func serve(conf config.Config, runner *application.Runner, m *metrics.Metrics, s schedule.Schedule, logger log.FieldLogger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	d := daemon.NewDaemon(s, func() (bool, string) {
+		summary := runner.Finish(runner.Run())
+		return application.Succeeded(summary.ExitCode), summary.Status
+	}, logger)
+
+	serverErrs := make(chan error, 1)
+	if conf.Daemon.Listen != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/health", d.HealthHandler())
+		mux.Handle("/metrics", m.Handler())
+		go func() {
+			err := daemon.ListenAndServe(ctx, conf.Daemon.Listen, mux, logger)
+			if err != nil {
+				stop() //without the endpoint nobody notices when the runs fail
+			}
+			serverErrs <- err
+		}()
+	} else {
+		serverErrs <- nil
+	}
+
+	err := d.Run(ctx)
+	stop() //without the runs, the endpoint would report a healthy daemon which does nothing
+	return errors.Join(err, <-serverErrs)
+}
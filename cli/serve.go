@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/api"
+	"github.com/ynori7/tvshows/application"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/daemon"
+	"github.com/ynori7/tvshows/metrics"
+	"github.com/ynori7/tvshows/premieres"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+	"github.com/ynori7/tvshows/web"
+)
+
+// webCommand serves the UI for the reports in the output directory until SIGINT or SIGTERM
+func webCommand(e env, fs *flag.FlagSet, args []string) int {
+	configFile := fs.String("config", "", "the path to the configuration yaml, for the title, templates and logging (optional)")
+	output := fs.String("output", "out", "the path where the reports were saved")
+	listen := fs.String("listen", "127.0.0.1:8082", "the address to listen on")
+	cliConf := new(config.CliConfig)
+	config.RegisterLoggingFlags(fs, cliConf)
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+
+	conf, logger, code, ok := e.setup(*cliConf, *configFile, true)
+	if !ok {
+		return code
+	}
+
+	templates, err := view.NewTemplates(conf.Report.TemplatesDir, conf.Report.Theme)
+	if err != nil {
+		return fail(logger, err, "Error loading templates")
+	}
+
+	server, err := web.NewServer(*output, conf.Title, templates, logger)
+	if err != nil {
+		return fail(logger, err, "Error creating the web UI")
+	}
+
+	return listenAndServe(*listen, server.Handler(), logger, "Error serving the web UI")
+}
+
+// apiCommand serves the JSON API until SIGINT or SIGTERM. The runs it triggers use the same flags as the
+// premieres command.
+func apiCommand(e env, fs *flag.FlagSet, args []string) int {
+	listen := fs.String("listen", "127.0.0.1:8081", "the address to listen on")
+	cliConf := config.RegisterRunFlags(fs)
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+
+	conf, logger, code, ok := e.setup(*cliConf, cliConf.ConfigFile, false)
+	if !ok {
+		return code
+	}
+
+	templates, err := view.NewTemplates(conf.Report.TemplatesDir, conf.Report.Theme)
+	if err != nil {
+		return fail(logger, err, "Error loading templates")
+	}
+	if _, err := view.NewRenderer(cliConf.Format, templates); err != nil {
+		return fail(logger, err, "Invalid format")
+	}
+
+	m := metrics.New()
+	runner := application.NewRunner(conf, application.NewOptions(*cliConf), templates, logger, m, nil)
+	server := api.NewServer(
+		func() api.PremieresSource {
+			return premieres.NewPremieresClient(conf)
+		},
+		tvshow.NewImdbClient(conf, logger, m),
+		func() application.Summary {
+			return runner.Finish(runner.Run())
+		},
+		conf.Api.Token,
+		logger,
+	)
+
+	return listenAndServe(*listen, server.Handler(), logger, "Error serving the API")
+}
+
+func listenAndServe(addr string, handler http.Handler, logger log.FieldLogger, msg string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := daemon.ListenAndServe(ctx, addr, handler, logger); err != nil {
+		return fail(logger, err, msg)
+	}
+	return application.ExitOk
+}
+
+// fail logs why the command couldn't start and returns the exit code for it
+func fail(logger log.FieldLogger, err error, msg string) int {
+	logger.WithFields(log.Fields{"Logger": "main", "error": err}).Error(msg)
+	return application.ExitConfig
+}
@@ -2,22 +2,52 @@ package config
 
 import "flag"
 
-var CliConf CliConfig
-
+// CliConfig holds the flags of the commands
 type CliConfig struct {
 	ConfigFile        string
 	OutputPath        string //optional
 	LastProcessedPath string //optional
+	Format            string //optional
+	SummaryFile       string //optional
+	LogLevel          string //optional, overrides the config
+	LogFormat         string //optional, overrides the config
+	Serve             bool   //keep running and do the runs on the schedule
 }
 
-func ParseCliFlags() {
-	configFile := flag.String("config", "", "the path to the configuration yaml")
-	lastProcessedPath := flag.String("last-processed-path", ".", "the path where the last processed date file should be saved")
-	output := flag.String("output", "out", "the path where output files should be saved")
+// RegisterCliFlags defines the flags of the premieres command on the flag set: those of a run and --serve.
+// They're in the returned config once it's parsed.
+func RegisterCliFlags(fs *flag.FlagSet) *CliConfig {
+	c := RegisterRunFlags(fs)
+	fs.BoolVar(&c.Serve, "serve", false, "keep running and generate the report on the schedule from the config")
+	return c
+}
 
-	flag.Parse()
+// RegisterRunFlags defines only the flags of a run, for the commands which trigger runs themselves
+func RegisterRunFlags(fs *flag.FlagSet) *CliConfig {
+	c := new(CliConfig)
+	fs.StringVar(&c.ConfigFile, "config", "", "the path to the configuration yaml")
+	fs.StringVar(&c.LastProcessedPath, "last-processed-path", ".", "the path where the last processed date file should be saved")
+	fs.StringVar(&c.OutputPath, "output", "out", "the path where output files should be saved")
+	fs.StringVar(&c.Format, "format", "html", "the format of the saved report: html, json, markdown or text")
+	fs.StringVar(&c.SummaryFile, "summary-file", "", "the path where a JSON summary of the run should be saved")
+	RegisterLoggingFlags(fs, c)
+	return c
+}
+
+// RegisterLoggingFlags defines only the flags which override the logging config, for the commands which
+// don't do a run
+func RegisterLoggingFlags(fs *flag.FlagSet, c *CliConfig) {
+	fs.StringVar(&c.LogLevel, "log-level", "", "the log level: debug, info, warning or error (overrides the config)")
+	fs.StringVar(&c.LogFormat, "log-format", "", "the log format: text or json (overrides the config)")
+}
 
-	CliConf.ConfigFile = *configFile
-	CliConf.LastProcessedPath = *lastProcessedPath
-	CliConf.OutputPath = *output
+// Logging returns the logging config with the flags applied
+func (c CliConfig) Logging(conf Logging) Logging {
+	if c.LogLevel != "" {
+		conf.Level = c.LogLevel
+	}
+	if c.LogFormat != "" {
+		conf.Format = c.LogFormat
+	}
+	return conf
 }
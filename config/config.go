@@ -3,13 +3,106 @@ package config
 import (
 	"strings"
 
+	"github.com/ynori7/tvshows/streamer"
 	yaml "gopkg.in/yaml.v2"
 )
 
 type Config struct {
-	Title      string
-	MainGenres []string `yaml:"main_genres,flow"`
-	Email      Email
+	Title            string
+	MainGenres       []string `yaml:"main_genres,flow"`
+	Watchlist        Watchlist
+	Ratings          Ratings
+	Recommendation   Recommendation
+	Report           Report
+	Streamers        []streamer.Provider //added to or replacing the default streaming services and networks
+	Subscriptions    []string            `yaml:"subscriptions,flow"` //the streaming services and networks we have
+	SubscriptionMode string              `yaml:"subscription_mode"`
+	Region           string              //country code, e.g. DE
+	Availability     Availability
+	Feed             Feed
+	Calendar         Calendar
+	Posters          Posters
+	Email            Email
+	Logging          Logging
+	Metrics          Metrics
+	Daemon           Daemon
+	Api              Api
+}
+
+type Daemon struct {
+	Schedule string //cron expression, e.g. "0 14 * * 1", in local time
+	Listen   string //the address of the health and metrics endpoints, e.g. ":8080"
+}
+
+type Api struct {
+	Token string //the bearer token which is needed to start runs, e.g. from TVSHOWS_API_TOKEN
+}
+
+type Metrics struct {
+	Textfile string //where to save the metrics for the node_exporter textfile collector after each run, e.g. tvshows.prom
+}
+
+const (
+	LogFormatText = "text"
+	LogFormatJson = "json"
+)
+
+type Logging struct {
+	Level  string //debug, info, warning or error
+	Format string //text or json
+}
+
+// Availability is the source of the regional availability data
+type Availability struct {
+	File string //local json or csv dataset
+	Url  string //availability provider
+}
+
+const (
+	SubscriptionModeFilter  = "filter"  //leave out the shows which aren't available on our services
+	SubscriptionModeSection = "section" //put them in a separate section
+)
+
+type Watchlist struct {
+	Files []string `yaml:"files,flow"` //IMDB csv exports or plain text lists
+}
+
+const (
+	defaultPromoteThreshold  = 8
+	defaultSuppressThreshold = 5
+)
+
+type Ratings struct {
+	Files             []string `yaml:"files,flow"`         //IMDB ratings csv exports
+	DroppedFiles      []string `yaml:"dropped_files,flow"` //shows we stopped watching, same formats as the watchlist
+	PromoteThreshold  int      `yaml:"promote_threshold"`
+	SuppressThreshold int      `yaml:"suppress_threshold"`
+}
+
+type Feed struct {
+	Enabled    bool
+	MaxEntries int    `yaml:"max_entries"`
+	Link       string //where the feed is published
+}
+
+type Calendar struct {
+	Enabled    bool
+	MaxAgeDays int `yaml:"max_age_days"` //events older than this are dropped
+}
+
+type Posters struct {
+	Enabled       bool
+	Width         int //of the thumbnails, in pixels
+	EmailBudgetKb int `yaml:"email_budget_kb"` //the maximum size of the email with the attached posters
+}
+
+const defaultEmailBudgetKb = 1024
+
+func (p Posters) GetEmailBudget() int {
+	if p.EmailBudgetKb <= 0 {
+		return defaultEmailBudgetKb * 1024
+	}
+	return p.EmailBudgetKb * 1024
 }
 
 type Email struct {
@@ -32,6 +125,77 @@ func (c *Config) Parse(data []byte) error {
 	return yaml.Unmarshal(data, &c)
 }
 
+// GetPromoteThreshold returns the minimum personal rating for a returning series to be promoted
+func (r Ratings) GetPromoteThreshold() int {
+	if r.PromoteThreshold == 0 {
+		return defaultPromoteThreshold
+	}
+	return r.PromoteThreshold
+}
+
+// GetSuppressThreshold returns the personal rating at or below which a returning series is filtered out
+func (r Ratings) GetSuppressThreshold() int {
+	if r.SuppressThreshold == 0 {
+		return defaultSuppressThreshold
+	}
+	return r.SuppressThreshold
+}
+
+const defaultLikedThreshold = 7
+
+type Recommendation struct {
+	LikedThreshold int `yaml:"liked_threshold"` //shows rated at least this high are used to build the profile
+	LookupLimit    int `yaml:"lookup_limit"`    //how many liked shows to look up on IMDB for keywords and descriptions
+}
+
+// GetLikedThreshold returns the minimum personal rating for a show to count as liked
+func (r Recommendation) GetLikedThreshold() int {
+	if r.LikedThreshold == 0 {
+		return defaultLikedThreshold
+	}
+	return r.LikedThreshold
+}
+
+const (
+	SortByScore     = "score"
+	SortByRelevance = "relevance"
+	SortByDate      = "date"
+)
+
+const (
+	GroupByType     = "type"
+	GroupByGenre    = "genre"
+	GroupByStreamer = "streamer"
+	GroupByDate     = "date"
+)
+
+const (
+	EmptyReportSilent = "silent"
+	EmptyReportEmail  = "email"
+)
+
+const defaultHonorableMentionsMargin = 10
+
+type Report struct {
+	SortBy                  string `yaml:"sort_by"`                   //score, relevance or date
+	GroupBy                 string `yaml:"group_by"`                  //type (new and returning), genre, streamer or date
+	TopPick                 bool   `yaml:"top_pick"`                  //the best show of the week gets its own section
+	HonorableMentions       int    `yaml:"honorable_mentions"`        //how many shows just under the score threshold to list
+	HonorableMentionsMargin int    `yaml:"honorable_mentions_margin"` //how far under the score threshold they can be
+	FilteredAppendix        bool   `yaml:"filtered_appendix"`         //list the filtered out shows and why at the end
+	EmptyReport             string `yaml:"empty_report"`              //silent or email, when nothing is interesting
+	TemplatesDir            string `yaml:"templates_dir"`             //html templates which override the built-in ones
+	Theme                   string //light or dark
+}
+
+// GetHonorableMentionsMargin returns how many points under the score threshold a show can be to get an honorable mention
+func (r Report) GetHonorableMentionsMargin() int {
+	if r.HonorableMentionsMargin == 0 {
+		return defaultHonorableMentionsMargin
+	}
+	return r.HonorableMentionsMargin
+}
+
 func (c *Config) IsInterestingMainGenre(genres []string) bool {
 	for _, g := range genres {
 		if isContainedInList(g, c.MainGenres) {
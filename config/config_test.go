@@ -1,6 +1,8 @@
 package config
 
 import (
+	"flag"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,6 +22,52 @@ main_genres: #these genres are filtered first from the new releases list
   - "Action"
   - "Sci-fi"
   - "Anime"
+watchlist:
+  files:
+    - "watchlist.csv"
+    - "watchlist.txt"
+ratings:
+  files:
+    - "ratings.csv"
+  promote_threshold: 9
+recommendation:
+  lookup_limit: 10
+report:
+  sort_by: "relevance"
+  group_by: "genre"
+  top_pick: true
+  honorable_mentions: 3
+  filtered_appendix: true
+  empty_report: "email"
+  templates_dir: "/templates"
+  theme: "dark"
+streamers:
+  - name: "Joyn"
+    aliases: ["Joyn+"]
+    link: "https://www.joyn.de"
+subscriptions: ["Netflix", "Max"]
+subscription_mode: "section"
+region: "DE"
+availability:
+  file: "availability.json"
+feed:
+  enabled: true
+  max_entries: 50
+calendar:
+  enabled: true
+posters:
+  enabled: true
+  width: 150
+logging:
+  level: "warning"
+  format: "json"
+metrics:
+  textfile: "/var/lib/node_exporter/tvshows.prom"
+daemon:
+  schedule: "0 14 * * 1"
+  listen: ":8080"
+api:
+  token: "secret789"
 email:
   enabled: true
   private_key: "private123"
@@ -38,6 +86,39 @@ email:
 
 	assert.Equal(t, "rap-and-metal", c.Title)
 	assert.Equal(t, 10, len(c.MainGenres))
+	assert.Equal(t, []string{"watchlist.csv", "watchlist.txt"}, c.Watchlist.Files)
+	assert.Equal(t, []string{"ratings.csv"}, c.Ratings.Files)
+	assert.Equal(t, 9, c.Ratings.GetPromoteThreshold())
+	assert.Equal(t, 5, c.Ratings.GetSuppressThreshold())
+	assert.Equal(t, 7, c.Recommendation.GetLikedThreshold())
+	assert.Equal(t, 10, c.Recommendation.LookupLimit)
+	assert.Equal(t, SortByRelevance, c.Report.SortBy)
+	assert.Equal(t, GroupByGenre, c.Report.GroupBy)
+	assert.True(t, c.Report.TopPick)
+	assert.Equal(t, 3, c.Report.HonorableMentions)
+	assert.True(t, c.Report.FilteredAppendix)
+	assert.Equal(t, EmptyReportEmail, c.Report.EmptyReport)
+	assert.Equal(t, 10, c.Report.GetHonorableMentionsMargin())
+	assert.Equal(t, "/templates", c.Report.TemplatesDir)
+	assert.Equal(t, "dark", c.Report.Theme)
+	assert.Equal(t, 1, len(c.Streamers))
+	assert.Equal(t, []string{"Joyn+"}, c.Streamers[0].Aliases)
+	assert.Equal(t, "https://www.joyn.de", c.Streamers[0].Link)
+	assert.Equal(t, []string{"Netflix", "Max"}, c.Subscriptions)
+	assert.Equal(t, SubscriptionModeSection, c.SubscriptionMode)
+	assert.Equal(t, "DE", c.Region)
+	assert.Equal(t, "availability.json", c.Availability.File)
+	assert.True(t, c.Feed.Enabled)
+	assert.Equal(t, 50, c.Feed.MaxEntries)
+	assert.True(t, c.Calendar.Enabled)
+	assert.Equal(t, 0, c.Calendar.MaxAgeDays)
+	assert.True(t, c.Posters.Enabled)
+	assert.Equal(t, 150, c.Posters.Width)
+	assert.Equal(t, 1024*1024, c.Posters.GetEmailBudget())
+	assert.Equal(t, Logging{Level: "warning", Format: LogFormatJson}, c.Logging)
+	assert.Equal(t, "/var/lib/node_exporter/tvshows.prom", c.Metrics.Textfile)
+	assert.Equal(t, Daemon{Schedule: "0 14 * * 1", Listen: ":8080"}, c.Daemon)
+	assert.Equal(t, "secret789", c.Api.Token)
 	assert.True(t, c.Email.Enabled)
 	assert.Equal(t, c.Email.PrivateKey, "private123")
 	assert.Equal(t, c.Email.PublicKey, "public456")
@@ -79,3 +160,105 @@ func Test_IsInterestingMainGenre(t *testing.T) {
 		assert.Equal(t, testdata.Expected, res, testcase)
 	}
 }
+
+func Test_CliConfig_Logging(t *testing.T) {
+	testcases := map[string]struct {
+		Cli      CliConfig
+		Expected Logging
+	}{
+		"No flags": {
+			Cli:      CliConfig{},
+			Expected: Logging{Level: "info", Format: LogFormatText},
+		},
+		"Flags override the config": {
+			Cli:      CliConfig{LogLevel: "debug", LogFormat: LogFormatJson},
+			Expected: Logging{Level: "debug", Format: LogFormatJson},
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//when
+		actual := testdata.Cli.Logging(Logging{Level: "info", Format: LogFormatText})
+
+		//then
+		assert.Equal(t, testdata.Expected, actual, testcase)
+	}
+}
+
+func Test_RegisterCliFlags(t *testing.T) {
+	//given
+	premieres := flag.NewFlagSet("premieres", flag.ContinueOnError)
+	api := flag.NewFlagSet("api", flag.ContinueOnError)
+
+	//when
+	RegisterCliFlags(premieres)
+	RegisterRunFlags(api)
+
+	//then
+	for _, name := range []string{"config", "output", "last-processed-path", "format", "summary-file", "log-level", "log-format"} {
+		assert.NotNil(t, premieres.Lookup(name), name)
+		assert.NotNil(t, api.Lookup(name), name)
+	}
+	assert.NotNil(t, premieres.Lookup("serve"))
+	assert.Nil(t, api.Lookup("serve"), "Only the premieres command can serve")
+}
+
+func Test_Validate(t *testing.T) {
+	testcases := map[string]struct {
+		Config   Config
+		Expected []string
+	}{
+		"Defaults": {
+			Config: Config{},
+		},
+		"Valid options": {
+			Config: Config{
+				Report:           Report{SortBy: SortByDate, GroupBy: GroupByStreamer, EmptyReport: EmptyReportEmail},
+				SubscriptionMode: SubscriptionModeSection,
+				Logging:          Logging{Format: LogFormatJson},
+			},
+		},
+		"Invalid options": {
+			Config: Config{
+				Report:           Report{SortBy: "rating", GroupBy: "network"},
+				SubscriptionMode: "hide",
+			},
+			Expected: []string{
+				`report.sort_by must be one of [score relevance date], not "rating"`,
+				`report.group_by must be one of [type genre streamer date], not "network"`,
+				`subscription_mode must be one of [filter section], not "hide"`,
+			},
+		},
+		"Email without the keys": {
+			Config: Config{Email: Email{Enabled: true, From: EmailRecipient{Address: "me@example.com"}, To: EmailRecipient{Address: "me@example.com"}}},
+			Expected: []string{
+				"email needs the private_key and public_key when it's enabled",
+			},
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//when
+		err := testdata.Config.Validate()
+
+		//then
+		if len(testdata.Expected) == 0 {
+			assert.NoError(t, err, testcase)
+			continue
+		}
+		assert.EqualError(t, err, strings.Join(testdata.Expected, "\n"), testcase)
+	}
+}
+
+func Test_ParseStrict(t *testing.T) {
+	//given
+	data := []byte("title: test\nreport:\n  sortby: date\n")
+
+	//when
+	var conf Config
+	err := conf.ParseStrict(data)
+
+	//then
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "field sortby not found")
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ApplyEnv(t *testing.T) {
+	testcases := map[string]struct {
+		Env           map[string]string
+		Expected      Config
+		ExpectedError string
+	}{
+		"No variables": {
+			Env:      map[string]string{},
+			Expected: Config{Title: "tv", MainGenres: []string{"Drama"}},
+		},
+		"Strings, numbers, booleans and lists": {
+			Env: map[string]string{
+				"TVSHOWS_TITLE":                   "weekly",
+				"TVSHOWS_MAIN_GENRES":             "Comedy, Drama,",
+				"TVSHOWS_EMAIL_ENABLED":           "true",
+				"TVSHOWS_EMAIL_PRIVATE_KEY":       "secret",
+				"TVSHOWS_EMAIL_TO_ADDRESS":        "me@example.com",
+				"TVSHOWS_REPORT_SORT_BY":          SortByDate,
+				"TVSHOWS_POSTERS_EMAIL_BUDGET_KB": "512",
+				"TVSHOWS_RATINGS_FILES":           "",
+				"OTHER_TITLE":                     "ignored",
+			},
+			Expected: Config{
+				Title:      "weekly",
+				MainGenres: []string{"Comedy", "Drama"},
+				Email:      Email{Enabled: true, PrivateKey: "secret", To: EmailRecipient{Address: "me@example.com"}},
+				Report:     Report{SortBy: SortByDate},
+				Posters:    Posters{EmailBudgetKb: 512},
+				Ratings:    Ratings{Files: []string{}},
+			},
+		},
+		"Invalid values": {
+			Env: map[string]string{
+				"TVSHOWS_EMAIL_ENABLED": "yes please",
+				"TVSHOWS_STREAMERS":     "Netflix",
+			},
+			ExpectedError: "TVSHOWS_STREAMERS: can only be set in the config file\nTVSHOWS_EMAIL_ENABLED: invalid boolean: yes please",
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		conf := Config{Title: "tv", MainGenres: []string{"Drama"}}
+		lookup := func(key string) (string, bool) {
+			value, ok := testdata.Env[key]
+			return value, ok
+		}
+
+		//when
+		err := conf.ApplyEnv(lookup)
+
+		//then
+		if testdata.ExpectedError != "" {
+			assert.EqualError(t, err, testdata.ExpectedError, testcase)
+			continue
+		}
+		require.NoError(t, err, testcase)
+		assert.Equal(t, testdata.Expected, conf, testcase)
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+/**
+ * ParseStrict is like Parse, but fails on keys which aren't in the Config, e.g. typos.
+ */
+func (c *Config) ParseStrict(data []byte) error {
+	return yaml.UnmarshalStrict(data, &c)
+}
+
+// Validate checks the options which only take certain values. Empty values mean the defaults.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(name string, value string, allowed ...string) {
+		if value != "" && !isContainedInList(value, allowed) {
+			errs = append(errs, fmt.Errorf("%s must be one of %v, not %q", name, allowed, value))
+		}
+	}
+
+	check("report.sort_by", c.Report.SortBy, SortByScore, SortByRelevance, SortByDate)
+	check("report.group_by", c.Report.GroupBy, GroupByType, GroupByGenre, GroupByStreamer, GroupByDate)
+	check("report.empty_report", c.Report.EmptyReport, EmptyReportSilent, EmptyReportEmail)
+	check("subscription_mode", c.SubscriptionMode, SubscriptionModeFilter, SubscriptionModeSection)
+	check("logging.format", c.Logging.Format, LogFormatText, LogFormatJson)
+
+	if c.Email.Enabled {
+		if c.Email.PrivateKey == "" || c.Email.PublicKey == "" {
+			errs = append(errs, fmt.Errorf("email needs the private_key and public_key when it's enabled"))
+		}
+		if c.Email.From.Address == "" || c.Email.To.Address == "" {
+			errs = append(errs, fmt.Errorf("email needs the from and to addresses when it's enabled"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/schedule"
+)
+
+// ErrNoNextRun means that the schedule doesn't match any time anymore
+var ErrNoNextRun = fmt.Errorf("the schedule has no next run")
+
+// RunFunc does one run and reports whether it succeeded and its status, e.g. ok or failed
+type RunFunc func() (success bool, status string)
+
+// Daemon does the runs on the schedule until it's stopped
+type Daemon struct {
+	schedule schedule.Schedule
+	run      RunFunc
+	logger   log.FieldLogger
+	now      func() time.Time
+
+	mu     sync.Mutex
+	health Health
+}
+
+// Health is the state reported by the health endpoint
+type Health struct {
+	Status        string     `json:"status"` //ok, or running while a run is in progress
+	LastRun       *time.Time `json:"last_run,omitempty"`
+	LastRunStatus string     `json:"last_run_status,omitempty"`
+	LastSuccess   *time.Time `json:"last_success,omitempty"`
+	NextRun       *time.Time `json:"next_run,omitempty"`
+}
+
+// The statuses of the daemon
+const (
+	StatusIdle    = "ok"
+	StatusRunning = "running"
+)
+
+func NewDaemon(s schedule.Schedule, run RunFunc, logger log.FieldLogger) *Daemon {
+	return &Daemon{
+		schedule: s,
+		run:      run,
+		logger:   logger,
+		now:      time.Now,
+		health:   Health{Status: StatusIdle},
+	}
+}
+
+// Run waits for the scheduled times and does the runs one after another, so they never overlap. When the
+// context is cancelled, a run in progress is finished before Run returns. It returns an error if it stopped
+// on its own, because there are no more runs.
+func (d *Daemon) Run(ctx context.Context) error {
+	logger := d.logger.WithFields(log.Fields{"Logger": "Daemon"})
+
+	for {
+		next := d.schedule.Next(d.now())
+		if next.IsZero() {
+			return ErrNoNextRun
+		}
+		d.update(func(h *Health) { h.NextRun = &next })
+		logger.WithFields(log.Fields{"next": next}).Info("Waiting for the next run")
+
+		timer := time.NewTimer(next.Sub(d.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logger.Info("Stopping")
+			return nil
+		case <-timer.C:
+		}
+
+		d.runOnce()
+	}
+}
+
+func (d *Daemon) runOnce() {
+	d.update(func(h *Health) { h.Status = StatusRunning })
+	success, status := d.run()
+
+	finished := d.now()
+	d.update(func(h *Health) {
+		h.Status = StatusIdle
+		h.LastRun = &finished
+		h.LastRunStatus = status
+		if success {
+			h.LastSuccess = &finished
+		}
+	})
+}
+
+func (d *Daemon) update(f func(h *Health)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f(&d.health)
+}
+
+// Health returns the current state
+func (d *Daemon) Health() Health {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.health
+}
+
+// HealthHandler serves the state as JSON, e.g. on /health
+func (d *Daemon) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(d.Health()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/schedule"
+)
+
+func Test_Daemon_Run(t *testing.T) {
+	//given
+	s, err := schedule.Parse("* * * * *")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	d := NewDaemon(s, func() (bool, string) {
+		runs++
+		if runs == 2 {
+			cancel()
+			return false, "failed"
+		}
+		return true, "ok"
+	}, log.StandardLogger())
+
+	//the next run is always a few milliseconds away
+	now := time.Date(2020, 6, 8, 13, 59, 59, 990000000, time.UTC)
+	d.now = func() time.Time { return now }
+
+	//when
+	err = d.Run(ctx)
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, 2, runs)
+	health := d.Health()
+	assert.Equal(t, StatusIdle, health.Status)
+	assert.Equal(t, "failed", health.LastRunStatus)
+	require.NotNil(t, health.LastSuccess)
+	require.NotNil(t, health.NextRun)
+	assert.Equal(t, time.Date(2020, 6, 8, 14, 0, 0, 0, time.UTC), *health.NextRun)
+}
+
+func Test_Daemon_Run_NoNextRun(t *testing.T) {
+	//given
+	runs := 0
+	d := NewDaemon(schedule.Schedule{}, func() (bool, string) {
+		runs++
+		return true, "ok"
+	}, log.StandardLogger())
+
+	//when
+	err := d.Run(context.Background())
+
+	//then
+	assert.Equal(t, ErrNoNextRun, err)
+	assert.Equal(t, 0, runs)
+}
+
+func Test_Daemon_HealthHandler(t *testing.T) {
+	//given
+	s, err := schedule.Parse("0 14 * * 1")
+	require.NoError(t, err)
+	d := NewDaemon(s, func() (bool, string) { return true, "ok" }, log.StandardLogger())
+	d.now = func() time.Time { return time.Date(2020, 6, 8, 13, 0, 0, 0, time.UTC) }
+	d.runOnce()
+	rec := httptest.NewRecorder()
+
+	//when
+	d.HealthHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+
+	//then
+	assert.Equal(t, 200, rec.Code)
+	var health map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+	assert.Equal(t, "ok", health["status"])
+	assert.Equal(t, "ok", health["last_run_status"])
+	assert.Equal(t, "2020-06-08T13:00:00Z", health["last_success"])
+	assert.NotContains(t, health, "next_run")
+}
@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ErrLocked means that another run holds the lock
+var ErrLocked = fmt.Errorf("another run is in progress")
+
+// Lock keeps runs from overlapping, also between processes, e.g. the daemon and a manual run
+type Lock struct {
+	path string
+}
+
+// AcquireLock creates the lock file with the PID of this process. If the process in an existing lock file
+// isn't running anymore, the lock is left over from a run which crashed, so it's taken over.
+func AcquireLock(path string) (*Lock, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = f.WriteString(strconv.Itoa(os.Getpid()))
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(path)
+				return nil, err
+			}
+			return &Lock{path: path}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if !isStale(path) {
+			return nil, ErrLocked
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, ErrLocked
+}
+
+// isStale checks whether the process which holds the lock is gone. A lock file without a PID is still being
+// written by the run which just created it.
+func isStale(path string) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil || pid <= 0 {
+		return false
+	}
+	return !isRunning(pid)
+}
+
+// isRunning sends the null signal, which only checks whether the process exists
+func isRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM) //EPERM: it's running as another user
+}
+
+// Release removes the lock file
+func (l *Lock) Release() error {
+	return os.Remove(l.path)
+}
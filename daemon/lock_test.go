@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AcquireLock(t *testing.T) {
+	//given
+	path := filepath.Join(t.TempDir(), "tvshows.lock")
+
+	//when
+	lock, err := AcquireLock(path)
+
+	//then
+	require.NoError(t, err)
+	_, err = AcquireLock(path)
+	assert.Equal(t, ErrLocked, err, "The second run shouldn't get the lock")
+
+	require.NoError(t, lock.Release())
+	lock, err = AcquireLock(path)
+	require.NoError(t, err, "The lock should be free again")
+	require.NoError(t, lock.Release())
+}
+
+func Test_AcquireLock_Existing(t *testing.T) {
+	//a process which has finished
+	cmd := exec.Command("true")
+	require.NoError(t, cmd.Run())
+
+	testcases := map[string]struct {
+		Content  string
+		Expected error
+	}{
+		"Held by a running process": {
+			Content:  strconv.Itoa(os.Getpid()),
+			Expected: ErrLocked,
+		},
+		"Left over from a crash": {
+			Content: strconv.Itoa(cmd.Process.Pid),
+		},
+		"Still being written": {
+			Content:  "",
+			Expected: ErrLocked,
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		path := filepath.Join(t.TempDir(), "tvshows.lock")
+		require.NoError(t, os.WriteFile(path, []byte(testdata.Content), 0644), testcase)
+
+		//when
+		lock, err := AcquireLock(path)
+
+		//then
+		if testdata.Expected != nil {
+			assert.Equal(t, testdata.Expected, err, testcase)
+			continue
+		}
+		require.NoError(t, err, testcase)
+		content, err := os.ReadFile(path)
+		require.NoError(t, err, testcase)
+		assert.Equal(t, strconv.Itoa(os.Getpid()), string(content), testcase)
+		require.NoError(t, lock.Release(), testcase)
+	}
+}
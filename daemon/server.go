@@ -0,0 +1,32 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// ListenAndServe serves the handler until the context is cancelled, then waits for the open requests
+func ListenAndServe(ctx context.Context, addr string, handler http.Handler, logger log.FieldLogger) error {
+	server := &http.Server{Addr: addr, Handler: handler}
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.WithFields(log.Fields{"Logger": "ListenAndServe", "addr": addr}).Info("Listening")
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,27 @@
+package email
+
+import "encoding/base64"
+
+// InlineImage is an image attached to the email, which the html refers to as cid:<ContentID>
+type InlineImage struct {
+	ContentID   string
+	ContentType string
+	Filename    string
+	Data        []byte
+}
+
+// FitInlineImages picks the images, in order, which fit into the size budget of the message together with
+// the html. Images which don't fit are skipped, so a smaller one later on can still be attached.
+func FitInlineImages(htmlSize int, images []InlineImage, budget int) []InlineImage {
+	size := htmlSize
+	fitting := make([]InlineImage, 0, len(images))
+	for _, img := range images {
+		encoded := base64.StdEncoding.EncodedLen(len(img.Data)) //attachments are sent base64 encoded
+		if size+encoded > budget {
+			continue
+		}
+		size += encoded
+		fitting = append(fitting, img)
+	}
+	return fitting
+}
@@ -0,0 +1,27 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FitInlineImages(t *testing.T) {
+	//given
+	images := []InlineImage{
+		{ContentID: "big", Data: make([]byte, 600)},    //800 bytes encoded
+		{ContentID: "small", Data: make([]byte, 150)},  //200 bytes encoded
+		{ContentID: "medium", Data: make([]byte, 300)}, //400 bytes encoded
+	}
+
+	//when
+	fitting := FitInlineImages(100, images, 1200)
+
+	//then
+	ids := make([]string, 0)
+	for _, img := range fitting {
+		ids = append(ids, img.ContentID)
+	}
+	assert.Equal(t, []string{"big", "small"}, ids)
+	assert.Empty(t, FitInlineImages(2000, images, 1200), "Nothing fits when the html alone is too big")
+}
@@ -1,6 +1,8 @@
 package email
 
 import (
+	"encoding/base64"
+
 	"github.com/mailjet/mailjet-apiv3-go"
 	"github.com/ynori7/tvshows/config"
 )
@@ -17,7 +19,22 @@ func NewMailer(conf config.Config) Mailer {
 	}
 }
 
-func (m Mailer) SendMail(subject string, htmlBody string) error {
+func (m Mailer) SendMail(subject string, htmlBody string, images ...InlineImage) error {
+	var attachments *mailjet.InlinedAttachmentsV31
+	if len(images) > 0 {
+		attachments = &mailjet.InlinedAttachmentsV31{}
+	}
+	for _, img := range images {
+		*attachments = append(*attachments, mailjet.InlinedAttachmentV31{
+			AttachmentV31: mailjet.AttachmentV31{
+				ContentType: img.ContentType,
+				Filename: img.Filename,
+				Base64Content: base64.StdEncoding.EncodeToString(img.Data),
+			},
+			ContentID: img.ContentID,
+		})
+	}
+
 	messagesInfo := []mailjet.InfoMessagesV31 {
 		{
 			From: &mailjet.RecipientV31{
@@ -32,6 +49,7 @@ func (m Mailer) SendMail(subject string, htmlBody string) error {
 			},
 			Subject: subject,
 			HTMLPart: htmlBody,
+			InlinedAttachments: attachments,
 		},
 	}
 	messages := mailjet.MessagesV31{Info: messagesInfo }
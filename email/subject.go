@@ -7,3 +7,7 @@ import (
 func GetNewReleasesSubjectLine(startDate string, endDate string) string {
 	return fmt.Sprintf("Newest premieres from %s through %s", startDate, endDate)
 }
+
+func GetNoNewReleasesSubjectLine(startDate string, endDate string) string {
+	return fmt.Sprintf("Nothing interesting premiered from %s through %s", startDate, endDate)
+}
@@ -5,35 +5,144 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/availability"
 	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/metrics"
 	"github.com/ynori7/tvshows/premieres"
+	"github.com/ynori7/tvshows/ratings"
+	"github.com/ynori7/tvshows/recommend"
+	"github.com/ynori7/tvshows/streamer"
 	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/watchlist"
 	"github.com/ynori7/workerpool"
 )
 
+// ShowSource looks up the premieres, e.g. the tvshow.ImdbClient
+type ShowSource interface {
+	SearchForTvSeriesTitle(searchTitle string) (string, error)
+	GetTvShowData(link string) (*tvshow.TvShow, error)
+}
+
 type Enricher struct {
 	conf               config.Config
 	potentialPremieres *premieres.PremiereList
-	tvshowClient       tvshow.ImdbClient
+	tvshowClient       ShowSource
+	watchlist          watchlist.Watchlist
+	ratingsHistory     ratings.History
+	profile            recommend.Scorer
+	availability       availability.Resolver
+	streamers          streamer.Mapping
+	logger             log.FieldLogger
+	metrics            *metrics.Metrics
+}
+
+var (
+	ErrScoreTooLow         = fmt.Errorf("score is too low")
+	ErrSuppressedByRatings = fmt.Errorf("suppressed by personal ratings")
+)
+
+const (
+	minScoreNew       = 20
+	minScoreReturning = 40
+)
+
+// Result holds the interesting series and the ones which were filtered out
+type Result struct {
+	Series     []tvshow.TvShow
+	Rejected   []Rejection
+	NearMisses []tvshow.TvShow //filtered out for a score just under the threshold, best first
+}
+
+// The reason codes of the rejections
+const (
+	CodeGenreMismatch = "genre_mismatch"
+	CodeNotFound      = "not_found" //no IMDB search result
+	CodeLookupFailed  = "lookup_failed"
+	CodeScoreTooLow   = "score_too_low"
+	CodeSuppressed    = "suppressed_by_ratings"
+	CodeUnavailable   = "unavailable" //not on our subscribed services, decided by the application
+)
+
+// Rejection is a premiere which didn't make it into the results
+type Rejection struct {
+	Title  string
+	Code   string
+	Reason string
+	Score  int            //zero if the series wasn't found
+	Link   string         //to IMDB, if the series was found
+	Series *tvshow.TvShow //if the series was found, with the details as far as they were looked up
 }
 
-var ErrScoreTooLow = fmt.Errorf("score is too low")
+// premiereError keeps track of which premiere failed
+type premiereError struct {
+	title    string
+	err      error
+	series   *tvshow.TvShow //set if the series was found
+	nearMiss bool           //if the score was just under the threshold
+}
+
+// rejection turns the error into a rejection record
+func (e premiereError) rejection() Rejection {
+	r := Rejection{Title: e.title, Reason: e.err.Error(), Code: CodeLookupFailed}
+	switch {
+	case errors.Is(e.err, ErrScoreTooLow):
+		r.Code = CodeScoreTooLow
+	case errors.Is(e.err, ErrSuppressedByRatings):
+		r.Code = CodeSuppressed
+	case errors.Is(e.err, tvshow.ErrNoResult):
+		r.Code = CodeNotFound
+	}
+	if e.series != nil {
+		r.Score = e.series.Score
+		r.Link = e.series.Link
+		r.Series = e.series
+	}
+	return r
+}
+
+func (e premiereError) Error() string {
+	return fmt.Sprintf("%s: %s", e.err, e.title)
+}
+
+func (e premiereError) Unwrap() error {
+	return e.err
+}
 
-func NewEnricher(conf config.Config, discographyClient tvshow.ImdbClient, premieres *premieres.PremiereList) Enricher {
+func NewEnricher(conf config.Config, discographyClient ShowSource, premieres *premieres.PremiereList, watchlist watchlist.Watchlist, ratingsHistory ratings.History, profile recommend.Scorer, availability availability.Resolver, logger log.FieldLogger, m *metrics.Metrics) Enricher {
 	return Enricher{
 		conf:               conf,
 		potentialPremieres: premieres,
 		tvshowClient:       discographyClient,
+		watchlist:          watchlist,
+		ratingsHistory:     ratingsHistory,
+		profile:            profile,
+		availability:       availability,
+		streamers:          streamer.NewMapping(conf.Streamers),
+		logger:             logger,
+		metrics:            m,
 	}
 }
 
-func (f Enricher) FilterAndEnrich() []tvshow.TvShow {
-	logger := log.WithFields(log.Fields{"Logger": "FilterAndEnrich"})
+func (f Enricher) FilterAndEnrich() Result {
+	logger := f.logger.WithFields(log.Fields{"Logger": "FilterAndEnrich"})
 
 	//Process results
 	series := make([]tvshow.TvShow, 0)
+	rejected := make([]Rejection, 0)
+	nearMisses := make([]tvshow.TvShow, 0)
+
+	//The ones which the premieres client skipped for their genres
+	for _, p := range f.potentialPremieres.Skipped {
+		reason := "not an interesting genre"
+		if len(p.Genres) > 0 {
+			reason += ": " + strings.Join(p.Genres, "/")
+		}
+		rejected = append(rejected, Rejection{Title: p.Title, Code: CodeGenreMismatch, Reason: reason})
+	}
 
 	//Set up worker pool
 	workerPool := workerpool.NewWorkerPool(
@@ -43,13 +152,19 @@ func (f Enricher) FilterAndEnrich() []tvshow.TvShow {
 			series = append(series, *r)
 		},
 		func(err error) {
-			unwrappedErr := errors.Unwrap(err)
-			switch unwrappedErr {
-			case ErrScoreTooLow:
+			if errors.Is(err, ErrScoreTooLow) || errors.Is(err, ErrSuppressedByRatings) {
 				logger.WithFields(log.Fields{"error": err}).Info("Series was filtered out")
-			default:
+			} else {
 				logger.WithFields(log.Fields{"error": err}).Error("Error looking up series data")
 			}
+
+			var pErr premiereError
+			if errors.As(err, &pErr) {
+				rejected = append(rejected, pErr.rejection())
+				if pErr.nearMiss {
+					nearMisses = append(nearMisses, *pErr.series)
+				}
+			}
 		},
 		f.processPremiere,
 	)
@@ -59,35 +174,122 @@ func (f Enricher) FilterAndEnrich() []tvshow.TvShow {
 		logger.WithFields(log.Fields{"error": err}).Error("Error processing jobs")
 	}
 
-	//Sort the results
+	//Sort the results, the promoted ones first
 	sort.Slice(series, func(i, j int) bool {
+		if series[i].IsPromoted != series[j].IsPromoted {
+			return series[i].IsPromoted
+		}
+		if f.conf.Report.SortBy == config.SortByDate && !series[i].PremiereDate.Equal(series[j].PremiereDate) {
+			if series[i].PremiereDate.IsZero() || series[j].PremiereDate.IsZero() {
+				return series[j].PremiereDate.IsZero() //unknown dates last
+			}
+			return series[i].PremiereDate.Before(series[j].PremiereDate)
+		}
+		if f.conf.Report.SortBy == config.SortByRelevance && series[i].RelevanceScore != series[j].RelevanceScore {
+			return series[i].RelevanceScore > series[j].RelevanceScore
+		}
 		return series[i].Score > series[j].Score
 	})
 
-	return series
+	sort.Slice(nearMisses, func(i, j int) bool {
+		return nearMisses[i].Score > nearMisses[j].Score
+	})
+
+	return Result{
+		Series:     series,
+		Rejected:   rejected,
+		NearMisses: nearMisses,
+	}
+}
+
+// MinScore returns the score a new or returning series needs, unless it's on the watchlist or promoted
+func MinScore(isNew bool) int {
+	if isNew {
+		return minScoreNew
+	}
+	return minScoreReturning
 }
 
 func (f Enricher) processPremiere(job interface{}) (result interface{}, err error) {
 	j := job.(premieres.Premiere)
+	defer func(startedAt time.Time) { f.metrics.ObserveEnrichment(time.Since(startedAt)) }(time.Now())
 
 	imdbLink, err := f.tvshowClient.SearchForTvSeriesTitle(j.Title)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", err, j.Title)
+		return nil, premiereError{title: j.Title, err: err}
 	}
 
 	series, err := f.tvshowClient.GetTvShowData(imdbLink)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", err, j.Title)
+		return nil, premiereError{title: j.Title, err: err, series: &tvshow.TvShow{Title: j.Title, Link: imdbLink}}
 	}
 
-	if (j.IsNew && series.Score < 20) || (!j.IsNew && series.Score < 40) {
-		return nil, fmt.Errorf("%w: %s", ErrScoreTooLow, j.Title)
+	series.RelevanceScore = f.profile.Score(recommend.DocumentFromTvShow(*series))
+
+	//Shows on the watchlist are always included
+	series.OnWatchlist = f.watchlist.Contains(*series)
+
+	//Returning series are promoted or suppressed based on how we rated them
+	if rating, ok := f.ratingsHistory.Lookup(*series); ok {
+		series.PersonalRating = rating.Rating
+	}
+	if !j.IsNew && !series.OnWatchlist {
+		if f.ratingsHistory.IsDropped(*series) {
+			return nil, premiereError{title: j.Title, err: fmt.Errorf("%w: we dropped it", ErrSuppressedByRatings), series: series}
+		}
+		if series.PersonalRating > 0 && series.PersonalRating <= f.conf.Ratings.GetSuppressThreshold() {
+			return nil, premiereError{title: j.Title, err: fmt.Errorf("%w: we rated it %d", ErrSuppressedByRatings, series.PersonalRating), series: series}
+		}
+		series.IsPromoted = series.PersonalRating >= f.conf.Ratings.GetPromoteThreshold()
 	}
 
-	series.IsNewSeries = j.IsNew
-	series.StreamingOption = j.StreamingOption
+	minScore := MinScore(j.IsNew)
+	if !series.OnWatchlist && !series.IsPromoted && series.Score < minScore {
+		pErr := premiereError{title: j.Title, err: fmt.Errorf("%w: %d", ErrScoreTooLow, series.Score), series: series}
+		if f.conf.Report.HonorableMentions > 0 && series.Score >= minScore-f.conf.Report.GetHonorableMentionsMargin() {
+			f.applyPremiere(series, j)
+			pErr.nearMiss = true
+		}
+		return nil, pErr
+	}
+
+	f.applyPremiere(series, j)
 
 	return series, nil
 }
 
+// applyPremiere sets the details we know from the premieres list
+func (f Enricher) applyPremiere(series *tvshow.TvShow, j premieres.Premiere) {
+	series.IsNewSeries = j.IsNew
+	series.PremiereDate = j.Date
+	series.Network = j.Network
+	series.Season = j.Season
+	series.StreamingOptions = j.StreamingOptions
+	f.resolveRegionalAvailability(series)
+}
+
+// resolveRegionalAvailability replaces the scraped streamers with the ones for our region, if we know them
+func (f Enricher) resolveRegionalAvailability(series *tvshow.TvShow) {
+	if f.conf.Region == "" || f.availability == nil {
+		return
+	}
+
+	streamers, ok, err := f.availability.Lookup(series.ImdbId, f.conf.Region)
+	if err != nil {
+		f.logger.WithFields(log.Fields{"Logger": "resolveRegionalAvailability", "Title": series.Title, "error": err}).
+			Warn("Error looking up regional availability")
+		return
+	}
+	if !ok {
+		return
+	}
 
+	series.StreamingOptions = make([]streamer.Provider, 0, len(streamers))
+	for _, s := range streamers {
+		p, found := f.streamers.Lookup(string(s))
+		if !found {
+			p = streamer.Provider{Name: s, Type: streamer.Streaming}
+		}
+		series.StreamingOptions = append(series.StreamingOptions, p)
+	}
+}
@@ -0,0 +1,214 @@
+package enrich
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/premieres"
+	"github.com/ynori7/tvshows/ratings"
+	"github.com/ynori7/tvshows/recommend"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/watchlist"
+)
+
+// fakeShows finds the shows by their title and fails for the ones without details
+type fakeShows map[string]*tvshow.TvShow
+
+func (f fakeShows) SearchForTvSeriesTitle(title string) (string, error) {
+	if _, ok := f[title]; !ok {
+		return "", tvshow.ErrNoResult
+	}
+	return "https://www.imdb.com/title/" + title + "/", nil
+}
+
+func (f fakeShows) GetTvShowData(link string) (*tvshow.TvShow, error) {
+	for title, show := range f {
+		if link == "https://www.imdb.com/title/"+title+"/" {
+			if show == nil {
+				return nil, fmt.Errorf("status code error: 503")
+			}
+			s := *show
+			s.Link = link
+			return &s, nil
+		}
+	}
+	return nil, fmt.Errorf("status code error: 404")
+}
+
+func testEnricher(t *testing.T, conf config.Config, shows fakeShows, list *premieres.PremiereList) Enricher {
+	wl := watchlist.New()
+	wl.Add("tt0000001", "")
+
+	dropped := filepath.Join(t.TempDir(), "dropped.txt")
+	require.NoError(t, ioutil.WriteFile(dropped, []byte("tt0000002\n"), 0644))
+	history, err := ratings.Load(nil, []string{dropped})
+	require.NoError(t, err)
+	history.Add(ratings.Rating{ImdbId: "tt0000003", Rating: 4})
+	history.Add(ratings.Rating{ImdbId: "tt0000004", Rating: 9})
+
+	return NewEnricher(conf, shows, list, wl, history, recommend.Profile{}, nil, log.StandardLogger(), nil)
+}
+
+func Test_ProcessPremiere(t *testing.T) {
+	shows := fakeShows{
+		"Good":       {Title: "Good", ImdbId: "tt0000010", Score: 60},
+		"Mediocre":   {Title: "Mediocre", ImdbId: "tt0000011", Score: 30},
+		"Bad":        {Title: "Bad", ImdbId: "tt0000012", Score: 10},
+		"Watchlist":  {Title: "Watchlist", ImdbId: "tt0000001", Score: 10},
+		"Dropped":    {Title: "Dropped", ImdbId: "tt0000002", Score: 80},
+		"Rated low":  {Title: "Rated low", ImdbId: "tt0000003", Score: 80},
+		"Rated high": {Title: "Rated high", ImdbId: "tt0000004", Score: 10},
+		"Broken":     nil,
+	}
+
+	testcases := map[string]struct {
+		Premiere          premieres.Premiere
+		HonorableMentions int
+		ExpectedIncluded  bool
+		ExpectedCode      string
+		ExpectedReason    string
+		ExpectedNearMiss  bool
+		ExpectedPromoted  bool
+	}{
+		"Returning series with a good score": {
+			Premiere:         premieres.Premiere{Title: "Good"},
+			ExpectedIncluded: true,
+		},
+		"Returning series with a low score": {
+			Premiere:       premieres.Premiere{Title: "Mediocre"},
+			ExpectedCode:   CodeScoreTooLow,
+			ExpectedReason: "score is too low: 30",
+		},
+		"New series need a lower score": {
+			Premiere:         premieres.Premiere{Title: "Mediocre", IsNew: true},
+			ExpectedIncluded: true,
+		},
+		"New series with a low score": {
+			Premiere:     premieres.Premiere{Title: "Bad", IsNew: true},
+			ExpectedCode: CodeScoreTooLow,
+		},
+		"Watchlist bypasses the score": {
+			Premiere:         premieres.Premiere{Title: "Watchlist"},
+			ExpectedIncluded: true,
+		},
+		"Dropped": {
+			Premiere:       premieres.Premiere{Title: "Dropped"},
+			ExpectedCode:   CodeSuppressed,
+			ExpectedReason: "suppressed by personal ratings: we dropped it",
+		},
+		"Rated at the suppress threshold": {
+			Premiere:       premieres.Premiere{Title: "Rated low"},
+			ExpectedCode:   CodeSuppressed,
+			ExpectedReason: "suppressed by personal ratings: we rated it 4",
+		},
+		"Ratings don't suppress new series": {
+			Premiere:         premieres.Premiere{Title: "Rated low", IsNew: true},
+			ExpectedIncluded: true,
+		},
+		"Rated at the promote threshold bypasses the score": {
+			Premiere:         premieres.Premiere{Title: "Rated high"},
+			ExpectedIncluded: true,
+			ExpectedPromoted: true,
+		},
+		"Not found": {
+			Premiere:       premieres.Premiere{Title: "Unknown"},
+			ExpectedCode:   CodeNotFound,
+			ExpectedReason: "no result found",
+		},
+		"Lookup failed": {
+			Premiere:       premieres.Premiere{Title: "Broken"},
+			ExpectedCode:   CodeLookupFailed,
+			ExpectedReason: "status code error: 503",
+		},
+		"Near miss": {
+			Premiere:          premieres.Premiere{Title: "Mediocre"},
+			HonorableMentions: 3,
+			ExpectedCode:      CodeScoreTooLow,
+			ExpectedNearMiss:  true,
+		},
+		"Too far under the score for a near miss": {
+			Premiere:          premieres.Premiere{Title: "Bad"},
+			HonorableMentions: 3,
+			ExpectedCode:      CodeScoreTooLow,
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		conf := config.Config{Report: config.Report{HonorableMentions: testdata.HonorableMentions}}
+		enricher := testEnricher(t, conf, shows, &premieres.PremiereList{})
+
+		//when
+		result, err := enricher.processPremiere(testdata.Premiere)
+
+		//then
+		if testdata.ExpectedIncluded {
+			require.NoError(t, err, testcase)
+			series := result.(*tvshow.TvShow)
+			assert.Equal(t, testdata.Premiere.IsNew, series.IsNewSeries, testcase)
+			assert.Equal(t, testdata.ExpectedPromoted, series.IsPromoted, testcase)
+			continue
+		}
+
+		var pErr premiereError
+		require.ErrorAs(t, err, &pErr, testcase)
+		rejection := pErr.rejection()
+		assert.Equal(t, testdata.Premiere.Title, rejection.Title, testcase)
+		assert.Equal(t, testdata.ExpectedCode, rejection.Code, testcase)
+		if testdata.ExpectedReason != "" {
+			assert.Equal(t, testdata.ExpectedReason, rejection.Reason, testcase)
+		}
+		assert.Equal(t, testdata.ExpectedNearMiss, pErr.nearMiss, testcase)
+	}
+}
+
+func Test_FilterAndEnrich(t *testing.T) {
+	//given
+	shows := fakeShows{
+		"Good":       {Title: "Good", ImdbId: "tt0000010", Score: 60},
+		"Better":     {Title: "Better", ImdbId: "tt0000013", Score: 70},
+		"Mediocre":   {Title: "Mediocre", ImdbId: "tt0000011", Score: 30},
+		"Close":      {Title: "Close", ImdbId: "tt0000014", Score: 35},
+		"Rated high": {Title: "Rated high", ImdbId: "tt0000004", Score: 10},
+	}
+	list := &premieres.PremiereList{
+		Premieres: []premieres.Premiere{
+			{Title: "Good"}, {Title: "Better"}, {Title: "Mediocre"}, {Title: "Close"}, {Title: "Rated high"}, {Title: "Unknown"},
+		},
+		Skipped: []premieres.Premiere{
+			{Title: "Mark vs. The Mountain", Genres: []string{"Reality", "Sports"}},
+		},
+	}
+	conf := config.Config{Report: config.Report{HonorableMentions: 2}}
+
+	//when
+	result := testEnricher(t, conf, shows, list).FilterAndEnrich()
+
+	//then
+	titles := func(list []tvshow.TvShow) []string {
+		out := make([]string, 0, len(list))
+		for _, s := range list {
+			out = append(out, s.Title)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"Rated high", "Better", "Good"}, titles(result.Series), "the promoted ones first, then by score")
+	assert.Equal(t, []string{"Close", "Mediocre"}, titles(result.NearMisses))
+
+	codes := make(map[string]string, len(result.Rejected))
+	for _, r := range result.Rejected {
+		codes[r.Title] = r.Code
+	}
+	assert.Equal(t, map[string]string{
+		"Mark vs. The Mountain": CodeGenreMismatch,
+		"Mediocre":              CodeScoreTooLow,
+		"Close":                 CodeScoreTooLow,
+		"Unknown":               CodeNotFound,
+	}, codes)
+}
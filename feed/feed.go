@@ -0,0 +1,168 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+const defaultMaxEntries = 100
+
+// Entry is a reported show in the feed history
+type Entry struct {
+	Id          string
+	Title       string
+	Link        string
+	Image       string
+	Rating      string
+	RatingCount int
+	Score       int
+	Genres      []string
+	Streamers   []string
+	Description string
+	Published   time.Time
+}
+
+// Feed maintains the Atom and RSS feeds of the reported shows in the output directory. The
+// history of entries is kept in a JSON file next to them.
+type Feed struct {
+	conf       config.Config
+	outputPath string
+}
+
+func NewFeed(conf config.Config, outputPath string) Feed {
+	return Feed{
+		conf:       conf,
+		outputPath: outputPath,
+	}
+}
+
+func (f Feed) historyFile() string {
+	return filepath.Join(f.outputPath, fmt.Sprintf("%s-feed.json", f.conf.Title))
+}
+
+func (f Feed) AtomFile() string {
+	return filepath.Join(f.outputPath, fmt.Sprintf("%s.atom", f.conf.Title))
+}
+
+func (f Feed) RssFile() string {
+	return filepath.Join(f.outputPath, fmt.Sprintf("%s.rss", f.conf.Title))
+}
+
+// Update adds an entry for every show in the report and rewrites the feed files. Only the
+// newest entries are kept.
+func (f Feed) Update(report view.Report) error {
+	entries, err := f.loadHistory()
+	if err != nil {
+		return err
+	}
+
+	published := report.Run.FinishedAt
+	if published.IsZero() {
+		published = time.Now()
+	}
+
+	known := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		known[e.Id] = true
+	}
+	for _, show := range report.AllShows() {
+		e := newEntry(show, report.EndDate, published)
+		if !known[e.Id] {
+			known[e.Id] = true
+			entries = append(entries, e)
+		}
+	}
+
+	//Newest first, capped
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Published.After(entries[j].Published)
+	})
+	if max := f.maxEntries(); len(entries) > max {
+		entries = entries[:max]
+	}
+
+	if err := f.saveHistory(entries); err != nil {
+		return err
+	}
+
+	atom, err := renderAtom(f.conf, entries)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(f.AtomFile(), atom, 0644); err != nil {
+		return err
+	}
+
+	rss, err := renderRss(f.conf, entries)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.RssFile(), rss, 0644)
+}
+
+func (f Feed) maxEntries() int {
+	if f.conf.Feed.MaxEntries <= 0 {
+		return defaultMaxEntries
+	}
+	return f.conf.Feed.MaxEntries
+}
+
+func newEntry(show tvshow.TvShow, endDate string, published time.Time) Entry {
+	streamers := make([]string, 0, len(show.StreamingOptions))
+	for _, s := range show.StreamingOptions {
+		streamers = append(streamers, string(s.Name))
+	}
+
+	id := show.ImdbId
+	if id == "" {
+		id = show.Link
+	}
+
+	return Entry{
+		Id:          fmt.Sprintf("%s/%s", id, strings.ReplaceAll(endDate, " ", "-")), //a show can premiere again with a later season
+		Title:       show.Title,
+		Link:        show.Link,
+		Image:       show.Image,
+		Rating:      show.Rating.AverageRating.String(),
+		RatingCount: show.Rating.RatingCount,
+		Score:       show.Score,
+		Genres:      show.Genres,
+		Streamers:   streamers,
+		Description: show.Description,
+		Published:   published,
+	}
+}
+
+func (f Feed) loadHistory() ([]Entry, error) {
+	data, err := ioutil.ReadFile(f.historyFile())
+	if os.IsNotExist(err) {
+		return make([]Entry, 0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0)
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", f.historyFile(), err)
+	}
+	return entries, nil
+}
+
+func (f Feed) saveHistory(entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.historyFile(), data, 0644)
+}
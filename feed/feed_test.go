@@ -0,0 +1,62 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+func Test_Update(t *testing.T) {
+	//given
+	conf := config.Config{Title: "my-conf", Feed: config.Feed{Enabled: true, MaxEntries: 3}}
+	f := NewFeed(conf, t.TempDir())
+
+	firstWeek := view.Report{
+		EndDate: "June 4",
+		Run:     view.RunInfo{FinishedAt: time.Date(2020, 6, 8, 14, 0, 0, 0, time.UTC)},
+		Sections: []view.Section{{Id: view.SectionNew, Title: "New Series", Shows: []tvshow.TvShow{
+			{Title: "#BlackAF", ImdbId: "tt10311562", Link: "https://www.imdb.com/title/tt10311562/", Score: 39},
+			{Title: "Élite", ImdbId: "tt7134908", Link: "https://www.imdb.com/title/tt7134908/", Score: 70},
+		}}},
+	}
+	secondWeek := view.Report{
+		EndDate: "June 11",
+		Run:     view.RunInfo{FinishedAt: time.Date(2020, 6, 15, 14, 0, 0, 0, time.UTC)},
+		Sections: []view.Section{{Id: view.SectionReturning, Title: "Returning Series", Shows: []tvshow.TvShow{
+			{Title: "Game of Thrones", ImdbId: "tt0944947", Link: "https://www.imdb.com/title/tt0944947/", Score: 100,
+				Image: "https://m.media-amazon.com/images/got.jpg", Description: "Nine noble families fight for control"},
+			{Title: "Sweet Magnolias", ImdbId: "tt10240086", Link: "https://www.imdb.com/title/tt10240086/", Score: 55},
+		}}},
+	}
+
+	//when
+	require.NoError(t, f.Update(firstWeek))
+	require.NoError(t, f.Update(firstWeek)) //running it again doesn't duplicate anything
+	require.NoError(t, f.Update(secondWeek))
+
+	//then
+	data, err := ioutil.ReadFile(f.AtomFile())
+	require.NoError(t, err)
+	var atom atomFeed
+	require.NoError(t, xml.Unmarshal(data, &atom), "The atom feed should be valid xml")
+	require.Equal(t, 3, len(atom.Entries), "The feed should be capped")
+	assert.Equal(t, "Game of Thrones", atom.Entries[0].Title)
+	assert.Equal(t, "urn:tvshows:my-conf:tt0944947/June-11", atom.Entries[0].Id)
+	assert.Contains(t, atom.Entries[0].Content.Body, `<img src="https://m.media-amazon.com/images/got.jpg"`)
+	assert.Contains(t, atom.Entries[0].Content.Body, "Nine noble families fight for control")
+	assert.Equal(t, "2020-06-15T14:00:00Z", atom.Updated)
+
+	data, err = ioutil.ReadFile(f.RssFile())
+	require.NoError(t, err)
+	var rss rssFeed
+	require.NoError(t, xml.Unmarshal(data, &rss), "The rss feed should be valid xml")
+	require.Equal(t, 3, len(rss.Channel.Items))
+	assert.Equal(t, "https://www.imdb.com/title/tt0944947/", rss.Channel.Items[0].Link)
+}
@@ -0,0 +1,170 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/ynori7/tvshows/config"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Id      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Id        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published"`
+	Link      atomLink    `xml:"link"`
+	Summary   string      `xml:"summary"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Guid        rssGuid `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGuid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func feedTitle(conf config.Config) string {
+	return fmt.Sprintf("%s premieres", conf.Title)
+}
+
+func renderAtom(conf config.Config, entries []Entry) ([]byte, error) {
+	updated := time.Now()
+	if len(entries) > 0 {
+		updated = entries[0].Published
+	}
+
+	feed := atomFeed{
+		Id:      fmt.Sprintf("urn:tvshows:%s", conf.Title),
+		Title:   feedTitle(conf),
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: "tvshows"},
+	}
+	if conf.Feed.Link != "" {
+		feed.Links = append(feed.Links, atomLink{Href: conf.Feed.Link, Rel: "self"})
+	}
+
+	for _, e := range entries {
+		content, err := entryContent(e)
+		if err != nil {
+			return nil, err
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			Id:        fmt.Sprintf("urn:tvshows:%s:%s", conf.Title, e.Id),
+			Title:     e.Title,
+			Updated:   e.Published.UTC().Format(time.RFC3339),
+			Published: e.Published.UTC().Format(time.RFC3339),
+			Link:      atomLink{Href: e.Link, Rel: "alternate"},
+			Summary:   entrySummary(e),
+			Content:   atomContent{Type: "html", Body: content},
+		})
+	}
+
+	return marshal(feed)
+}
+
+func renderRss(conf config.Config, entries []Entry) ([]byte, error) {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         feedTitle(conf),
+			Link:          conf.Feed.Link,
+			Description:   "Interesting new and returning TV series",
+			LastBuildDate: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	for _, e := range entries {
+		content, err := entryContent(e)
+		if err != nil {
+			return nil, err
+		}
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			Guid:        rssGuid{Value: fmt.Sprintf("urn:tvshows:%s:%s", conf.Title, e.Id)},
+			PubDate:     e.Published.UTC().Format(time.RFC1123Z),
+			Description: content,
+		})
+	}
+
+	return marshal(feed)
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	out, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func entrySummary(e Entry) string {
+	return fmt.Sprintf("Rated %s/10 by %d users, score %d/100", e.Rating, e.RatingCount, e.Score)
+}
+
+var entryTemplate = template.Must(template.New("entry").
+	Funcs(template.FuncMap{"join": strings.Join}).
+	Parse(`{{ if .Image }}<p><img src="{{ .Image }}" alt="{{ .Title }} Poster" width="200"></p>{{ end }}
+<p><strong>{{ .Rating }}</strong>/10 ({{ .RatingCount }} ratings), score <strong>{{ .Score }}</strong>/100</p>
+{{ if .Genres }}<p>{{ join .Genres ", " }}</p>{{ end }}
+{{ if .Description }}<p>{{ .Description }}</p>{{ end }}
+{{ if .Streamers }}<p>Available on {{ join .Streamers ", " }}</p>{{ end }}
+<p><a href="{{ .Link }}">IMDB</a></p>`))
+
+// entryContent is the HTML body of the entry
+func entryContent(e Entry) (string, error) {
+	var b bytes.Buffer
+	if err := entryTemplate.Execute(&b, e); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
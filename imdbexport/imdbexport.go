@@ -0,0 +1,121 @@
+package imdbexport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+// Entry is a single row of an IMDB list, watchlist or ratings CSV export
+type Entry struct {
+	ImdbId      string
+	Title       string
+	TitleType   string //"TV Series", "TV Mini Series", "Movie", ...
+	Description string
+	Year        string
+	Genres      []string
+	Directors   []string
+	ImdbRating  float64
+	YourRating  int //only set in ratings exports, 0 when not rated
+	DateRated   string
+}
+
+// IsTvSeries returns true if the entry is a series rather than a movie or episode
+func (e Entry) IsTvSeries() bool {
+	return strings.HasPrefix(e.TitleType, "TV Series") || strings.HasPrefix(e.TitleType, "TV Mini") ||
+		strings.EqualFold(e.TitleType, "tvSeries") || strings.EqualFold(e.TitleType, "tvMiniSeries")
+}
+
+// ReadFile reads the IMDB CSV export at the given path
+func ReadFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// Read parses an IMDB CSV export. The columns are looked up by their header names since
+// the watchlist and ratings exports don't have the same layout.
+func Read(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		h = strings.TrimPrefix(h, "\ufeff") //excel likes to add a byte order mark
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := columns["const"]; !ok {
+		return nil, fmt.Errorf("missing Const column, this doesn't look like an IMDB export")
+	}
+
+	entries := make([]Entry, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		get := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		entry := Entry{
+			ImdbId:      tvshow.ParseImdbId(get("const")),
+			Title:       get("title"),
+			TitleType:   get("title type"),
+			Description: get("description"),
+			Year:        get("year"),
+			Genres:      splitList(get("genres")),
+			Directors:   splitList(get("directors")),
+			DateRated:   get("date rated"),
+		}
+		if entry.ImdbId == "" {
+			continue //garbage row
+		}
+		entry.ImdbRating, _ = strconv.ParseFloat(get("imdb rating"), 64)
+		entry.YourRating, _ = strconv.Atoi(get("your rating"))
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	list := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			list = append(list, p)
+		}
+	}
+	return list
+}
@@ -0,0 +1,49 @@
+package imdbexport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadFile_Watchlist(t *testing.T) {
+	//when
+	entries, err := ReadFile("testdata/watchlist.csv")
+
+	//then
+	require.NoError(t, err, "There was an error reading the watchlist")
+	require.Equal(t, 3, len(entries))
+	assert.Equal(t, "tt7134908", entries[0].ImdbId)
+	assert.Equal(t, "Élite", entries[0].Title)
+	assert.Equal(t, []string{"Crime", "Drama", "Thriller"}, entries[0].Genres)
+	assert.True(t, entries[0].IsTvSeries())
+	assert.Equal(t, "rewatch", entries[1].Description)
+	assert.Equal(t, 9.2, entries[1].ImdbRating)
+	assert.Equal(t, 0, entries[1].YourRating)
+	assert.False(t, entries[2].IsTvSeries())
+	assert.Equal(t, []string{"Christopher Nolan"}, entries[2].Directors)
+}
+
+func Test_ReadFile_Ratings(t *testing.T) {
+	//when
+	entries, err := ReadFile("testdata/ratings.csv")
+
+	//then
+	require.NoError(t, err, "There was an error reading the ratings")
+	require.Equal(t, 3, len(entries))
+	assert.Equal(t, "tt0944947", entries[0].ImdbId)
+	assert.Equal(t, 9, entries[0].YourRating)
+	assert.Equal(t, "2019-05-20", entries[0].DateRated)
+	assert.Equal(t, 3, entries[1].YourRating)
+	assert.Equal(t, []string{"Comedy"}, entries[1].Genres)
+}
+
+func Test_Read_NotAnExport(t *testing.T) {
+	//when
+	_, err := Read(strings.NewReader("title,rating\nGame of Thrones,9\n"))
+
+	//then
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/config"
+)
+
+const defaultLevel = log.InfoLevel
+
+// New creates the logger which is handed to all of the packages. An empty level means info and an
+// empty format means text.
+func New(conf config.Logging, out io.Writer) (*log.Logger, error) {
+	logger := log.New()
+	logger.SetOutput(out)
+
+	level := defaultLevel
+	if conf.Level != "" {
+		var err error
+		if level, err = log.ParseLevel(conf.Level); err != nil {
+			return nil, err
+		}
+	}
+	logger.SetLevel(level)
+
+	switch strings.ToLower(conf.Format) {
+	case "", config.LogFormatText:
+		logger.SetFormatter(&log.TextFormatter{
+			FullTimestamp: true,
+		})
+	case config.LogFormatJson:
+		logger.SetFormatter(&log.JSONFormatter{})
+	default:
+		return nil, fmt.Errorf("unsupported log format: %s", conf.Format)
+	}
+
+	return logger, nil
+}
@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/config"
+)
+
+func Test_New(t *testing.T) {
+	testcases := map[string]struct {
+		Conf          config.Logging
+		ExpectedLevel log.Level
+		ExpectedError bool
+	}{
+		"Defaults": {
+			Conf:          config.Logging{},
+			ExpectedLevel: log.InfoLevel,
+		},
+		"Debug as json": {
+			Conf:          config.Logging{Level: "debug", Format: "json"},
+			ExpectedLevel: log.DebugLevel,
+		},
+		"Unknown level": {
+			Conf:          config.Logging{Level: "loud"},
+			ExpectedError: true,
+		},
+		"Unknown format": {
+			Conf:          config.Logging{Format: "xml"},
+			ExpectedError: true,
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//when
+		logger, err := New(testdata.Conf, &bytes.Buffer{})
+
+		//then
+		if testdata.ExpectedError {
+			assert.Error(t, err, testcase)
+			continue
+		}
+		require.NoError(t, err, testcase)
+		assert.Equal(t, testdata.ExpectedLevel, logger.GetLevel(), testcase)
+	}
+}
+
+func Test_New_Json(t *testing.T) {
+	//given
+	out := &bytes.Buffer{}
+	logger, err := New(config.Logging{Format: config.LogFormatJson}, out)
+	require.NoError(t, err)
+
+	//when
+	logger.WithFields(log.Fields{"Logger": "test"}).Info("Hello")
+	logger.Debug("Not logged")
+
+	//then
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "test", entry["Logger"])
+	assert.Equal(t, "Hello", entry["msg"])
+	assert.Equal(t, "info", entry["level"])
+}
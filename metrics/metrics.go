@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// The results which are counted
+const (
+	ResultOk    = "ok"
+	ResultError = "error"
+	ResultHit   = "hit"
+	ResultMiss  = "miss"
+)
+
+var enrichmentBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics are the metrics of the runs. All of the methods can be called on nil, which records nothing,
+// so that the packages don't have to check whether metrics are enabled.
+type Metrics struct {
+	registry           *prometheus.Registry
+	premieresScraped   *prometheus.CounterVec
+	imdbRequests       *prometheus.CounterVec
+	cacheRequests      *prometheus.CounterVec
+	enrichmentDuration prometheus.Histogram
+	filtered           *prometheus.CounterVec
+	emails             *prometheus.CounterVec
+	runs               *prometheus.CounterVec
+	lastRun            prometheus.Gauge
+	lastRunDuration    prometheus.Gauge
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		premieresScraped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tvshows_premieres_scraped_total",
+			Help: "Premieres found in the premieres lists.",
+		}, []string{"source"}),
+		imdbRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tvshows_imdb_requests_total",
+			Help: "Requests to IMDB.",
+		}, []string{"kind", "result"}),
+		cacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tvshows_cache_requests_total",
+			Help: "Lookups in the local caches.",
+		}, []string{"cache", "result"}),
+		enrichmentDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "tvshows_enrichment_duration_seconds",
+			Help:    "How long looking up a premiere took.",
+			Buckets: enrichmentBuckets,
+		}),
+		filtered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tvshows_premieres_filtered_total",
+			Help: "Premieres which were left out of the report.",
+		}, []string{"reason"}),
+		emails: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tvshows_emails_total",
+			Help: "Emails which were sent or failed.",
+		}, []string{"result"}),
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tvshows_runs_total",
+			Help: "Finished runs.",
+		}, []string{"status"}),
+		lastRun: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "tvshows_last_run_timestamp_seconds",
+			Help: "When the last run finished.",
+		}),
+		lastRunDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "tvshows_last_run_duration_seconds",
+			Help: "How long the last run took.",
+		}),
+	}
+	m.registry.MustRegister(m.premieresScraped, m.imdbRequests, m.cacheRequests, m.enrichmentDuration, m.filtered,
+		m.emails, m.runs, m.lastRun, m.lastRunDuration)
+	return m
+}
+
+// Handler serves the metrics, e.g. on /metrics
+func (m *Metrics) Handler() http.Handler {
+	if m == nil {
+		return promhttp.HandlerFor(prometheus.NewRegistry(), promhttp.HandlerOpts{})
+	}
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// WriteTextfile saves the metrics for the node_exporter textfile collector. The file is replaced atomically,
+// so that the collector never reads a partial file.
+func (m *Metrics) WriteTextfile(path string) error {
+	if m == nil {
+		return nil
+	}
+	return prometheus.WriteToTextfile(path, m.registry)
+}
+
+func (m *Metrics) PremieresScraped(source string, count int) {
+	if m == nil {
+		return
+	}
+	m.premieresScraped.WithLabelValues(source).Add(float64(count))
+}
+
+// ImdbRequest counts a request of the kind (e.g. search or title)
+func (m *Metrics) ImdbRequest(kind string, err error) {
+	if m == nil {
+		return
+	}
+	m.imdbRequests.WithLabelValues(kind, result(err)).Inc()
+}
+
+// CacheLookup counts a hit or miss in the cache (e.g. posters)
+func (m *Metrics) CacheLookup(cache string, hit bool) {
+	if m == nil {
+		return
+	}
+	if hit {
+		m.cacheRequests.WithLabelValues(cache, ResultHit).Inc()
+	} else {
+		m.cacheRequests.WithLabelValues(cache, ResultMiss).Inc()
+	}
+}
+
+func (m *Metrics) ObserveEnrichment(duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.enrichmentDuration.Observe(duration.Seconds())
+}
+
+// Filtered counts a premiere which was left out for the reason code
+func (m *Metrics) Filtered(reason string) {
+	if m == nil {
+		return
+	}
+	m.filtered.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) EmailSent(err error) {
+	if m == nil {
+		return
+	}
+	m.emails.WithLabelValues(result(err)).Inc()
+}
+
+// RunFinished records the outcome of a run which took the duration
+func (m *Metrics) RunFinished(status string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.runs.WithLabelValues(status).Inc()
+	m.lastRun.Set(float64(time.Now().Unix()))
+	m.lastRunDuration.Set(duration.Seconds())
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultOk
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Metrics_WriteTextfile(t *testing.T) {
+	//given
+	m := New()
+	m.PremieresScraped("metacritic", 12)
+	m.ImdbRequest("title", nil)
+	m.ImdbRequest("title", nil)
+	m.ImdbRequest("search", errors.New("status code error: 503"))
+	m.CacheLookup("posters", true)
+	m.ObserveEnrichment(750 * time.Millisecond)
+	m.Filtered("genre")
+	m.EmailSent(nil)
+	m.RunFinished("ok", 3*time.Second)
+
+	path := filepath.Join(t.TempDir(), "tvshows.prom")
+
+	//when
+	err := m.WriteTextfile(path)
+
+	//then
+	require.NoError(t, err)
+	out, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	for _, line := range []string{
+		`tvshows_premieres_scraped_total{source="metacritic"} 12`,
+		`tvshows_imdb_requests_total{kind="search",result="error"} 1`,
+		`tvshows_imdb_requests_total{kind="title",result="ok"} 2`,
+		`tvshows_cache_requests_total{cache="posters",result="hit"} 1`,
+		`tvshows_enrichment_duration_seconds_bucket{le="1"} 1`,
+		`tvshows_enrichment_duration_seconds_count 1`,
+		`tvshows_premieres_filtered_total{reason="genre"} 1`,
+		`tvshows_emails_total{result="ok"} 1`,
+		`tvshows_runs_total{status="ok"} 1`,
+		`tvshows_last_run_duration_seconds 3`,
+	} {
+		assert.Contains(t, string(out), line)
+	}
+}
+
+func Test_Metrics_Handler(t *testing.T) {
+	//given
+	m := New()
+	m.RunFinished("failed", time.Second)
+
+	//when
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	//then
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Body.String(), `tvshows_runs_total{status="failed"} 1`)
+}
+
+func Test_Metrics_Nil(t *testing.T) {
+	//given
+	var m *Metrics
+
+	//when
+	m.ImdbRequest("title", nil)
+	m.RunFinished("ok", time.Second)
+	err := m.WriteTextfile(filepath.Join(t.TempDir(), "tvshows.prom"))
+
+	//then
+	assert.NoError(t, err)
+}
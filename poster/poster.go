@@ -0,0 +1,141 @@
+package poster
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png" //some posters are png
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/metrics"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+const (
+	Dir          = "posters" //the directory in the output path where the thumbnails are saved
+	defaultWidth = 120
+	jpegQuality  = 80
+	cacheName    = "posters" //in the metrics
+)
+
+// Poster is a thumbnail of a show's poster, saved in the output directory
+type Poster struct {
+	ImdbId string
+	Path   string //relative to the output directory
+	Data   []byte //jpeg
+}
+
+// ContentID identifies the poster when it's attached to the email
+func (p Poster) ContentID() string {
+	return "poster-" + p.ImdbId
+}
+
+type Downloader struct {
+	httpClient *http.Client
+	conf       config.Config
+	outputPath string
+	logger     log.FieldLogger
+	metrics    *metrics.Metrics
+}
+
+func NewDownloader(conf config.Config, outputPath string, logger log.FieldLogger, m *metrics.Metrics) Downloader {
+	return Downloader{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		conf:       conf,
+		outputPath: outputPath,
+		logger:     logger,
+		metrics:    m,
+	}
+}
+
+// Fetch downloads the posters of the shows and saves them as thumbnails. Thumbnails which were already
+// saved by a previous run are reused. Shows without a poster, or whose poster can't be fetched, are left out.
+func (d Downloader) Fetch(shows []tvshow.TvShow) map[string]Poster {
+	logger := d.logger.WithFields(log.Fields{"Logger": "Fetch"})
+
+	if err := os.MkdirAll(filepath.Join(d.outputPath, Dir), 0755); err != nil {
+		logger.WithFields(log.Fields{"error": err}).Warn("Error creating the posters directory")
+		return map[string]Poster{}
+	}
+
+	posters := make(map[string]Poster, len(shows))
+	for _, show := range shows {
+		if show.ImdbId == "" || show.Image == "" {
+			continue
+		}
+		if _, ok := posters[show.ImdbId]; ok {
+			continue
+		}
+
+		p, err := d.fetch(show)
+		if err != nil {
+			logger.WithFields(log.Fields{"Title": show.Title, "error": err}).Warn("Error fetching poster")
+			continue
+		}
+		posters[show.ImdbId] = p
+	}
+	return posters
+}
+
+func (d Downloader) fetch(show tvshow.TvShow) (Poster, error) {
+	p := Poster{
+		ImdbId: show.ImdbId,
+		Path:   filepath.ToSlash(filepath.Join(Dir, show.ImdbId+".jpg")),
+	}
+	file := filepath.Join(d.outputPath, p.Path)
+
+	//Reuse the thumbnail from a previous run
+	if data, err := ioutil.ReadFile(file); err == nil {
+		d.metrics.CacheLookup(cacheName, true)
+		p.Data = data
+		return p, nil
+	}
+	d.metrics.CacheLookup(cacheName, false)
+
+	res, err := d.httpClient.Get(thumbnailUrl(show.Image, d.width()))
+	if err != nil {
+		return p, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return p, fmt.Errorf("status code error: %d %s", res.StatusCode, res.Status)
+	}
+
+	img, _, err := image.Decode(res.Body)
+	if err != nil {
+		return p, err
+	}
+
+	var b bytes.Buffer
+	if err := jpeg.Encode(&b, Resize(img, d.width()), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return p, err
+	}
+	p.Data = b.Bytes()
+
+	return p, ioutil.WriteFile(file, p.Data, 0644)
+}
+
+func (d Downloader) width() int {
+	if d.conf.Posters.Width <= 0 {
+		return defaultWidth
+	}
+	return d.conf.Posters.Width
+}
+
+// thumbnailUrl asks the IMDB image server for a smaller version, so that the full-size poster doesn't need
+// to be downloaded. It's still resized locally, since the server doesn't always respect it.
+// e.g. https://m.media-amazon.com/images/M/MV5B...@._V1_.jpg becomes ...@._V1_SX240.jpg
+func thumbnailUrl(url string, width int) string {
+	if !strings.Contains(url, "media-amazon.com") || !strings.HasSuffix(url, "._V1_.jpg") {
+		return url
+	}
+	return strings.TrimSuffix(url, "._V1_.jpg") + fmt.Sprintf("._V1_SX%d.jpg", 2*width)
+}
@@ -0,0 +1,110 @@
+package poster
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+func Test_Fetch(t *testing.T) {
+	//given
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.URL.Path != "/poster.png" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		img := image.NewRGBA(image.Rect(0, 0, 400, 600))
+		for y := 0; y < 600; y++ {
+			for x := 0; x < 400; x++ {
+				img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+			}
+		}
+		require.NoError(t, png.Encode(rw, img))
+	}))
+	defer server.Close()
+
+	outputPath := t.TempDir()
+	downloader := NewDownloader(config.Config{Posters: config.Posters{Enabled: true, Width: 100}}, outputPath, log.StandardLogger(), nil)
+	shows := []tvshow.TvShow{
+		{Title: "Game of Thrones", ImdbId: "tt0944947", Image: server.URL + "/poster.png"},
+		{Title: "Broken", ImdbId: "tt0000001", Image: server.URL + "/missing.png"},
+		{Title: "No Poster", ImdbId: "tt0000002"},
+	}
+
+	//when
+	posters := downloader.Fetch(shows)
+
+	//then
+	require.Equal(t, 1, len(posters))
+	p := posters["tt0944947"]
+	assert.Equal(t, "posters/tt0944947.jpg", p.Path)
+	assert.Equal(t, "poster-tt0944947", p.ContentID())
+
+	saved, err := ioutil.ReadFile(filepath.Join(outputPath, p.Path))
+	require.NoError(t, err, "The thumbnail should be saved")
+	assert.Equal(t, p.Data, saved)
+	thumbnail, err := jpeg.Decode(bytes.NewReader(saved))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 150), thumbnail.Bounds())
+
+	//when fetching again, the saved thumbnail is reused
+	requests = 0
+	posters = downloader.Fetch(shows[:1])
+
+	//then
+	assert.Equal(t, 0, requests)
+	assert.Equal(t, p.Data, posters["tt0944947"].Data)
+}
+
+func Test_Resize(t *testing.T) {
+	//given
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+		img.Set(x, 1, color.RGBA{B: 255, A: 255})
+	}
+
+	//when
+	resized := Resize(img, 2)
+	unchanged := Resize(img, 10)
+
+	//then
+	assert.Equal(t, image.Rect(0, 0, 2, 1), resized.Bounds())
+	assert.Equal(t, color.RGBA{R: 127, B: 127, A: 255}, resized.At(0, 0), "The pixels should be averaged")
+	assert.Equal(t, img, unchanged, "Images should only be made smaller")
+}
+
+func Test_thumbnailUrl(t *testing.T) {
+	testcases := map[string]struct {
+		Url      string
+		Expected string
+	}{
+		"IMDB": {
+			Url:      "https://m.media-amazon.com/images/M/MV5BOGU4YjhlYTQtMWI3Ny00M2JiLWJhZTEtNzkzZmRmNDVmMmExXkEyXkFqcGdeQXVyMjIwNTI1MTM@._V1_.jpg",
+			Expected: "https://m.media-amazon.com/images/M/MV5BOGU4YjhlYTQtMWI3Ny00M2JiLWJhZTEtNzkzZmRmNDVmMmExXkEyXkFqcGdeQXVyMjIwNTI1MTM@._V1_SX240.jpg",
+		},
+		"Other": {
+			Url:      "https://example.com/poster.jpg",
+			Expected: "https://example.com/poster.jpg",
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		assert.Equal(t, testdata.Expected, thumbnailUrl(testdata.Url, 120), testcase)
+	}
+}
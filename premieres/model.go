@@ -1,16 +1,24 @@
 package premieres
 
-import "github.com/ynori7/tvshows/streamer"
+import (
+	"time"
+
+	"github.com/ynori7/tvshows/streamer"
+)
 
 type Premiere struct {
-	Title           string
-	IsNew           bool //if false, it's a new season of an older show
-	Genres          []string
-	StreamingOption streamer.Streamer
+	Title            string
+	IsNew            bool      //if false, it's a new season of an older show
+	Date             time.Time //zero if unknown
+	Network          string    //the network, or the first streamer if it's not on a network
+	Season           int       //zero if unknown
+	Genres           []string
+	StreamingOptions []streamer.Provider
 }
 
 type PremiereList struct {
 	StartDate string
 	EndDate   string
 	Premieres []Premiere
+	Skipped   []Premiere //the ones which weren't in an interesting genre
 }
@@ -3,6 +3,8 @@ package premieres
 import (
 	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,14 +14,23 @@ import (
 	"github.com/ynori7/tvshows/config"
 )
 
+// Source names the premieres list, e.g. in the metrics
+const Source = "metacritic"
+
 const premieresUrl = "https://www.metacritic.com/news/tv-calendar-archive-of-past-dates/"
 const oneWeek = 7
 
+var (
+	seasonRegex  = regexp.MustCompile(`/season-(\d+)/?`)
+	airTimeRegex = regexp.MustCompile(`(?i)\b\d{1,2}(:\d{2})?\s*(am|pm|a|p)\b`) //e.g. 9p or 8:30p
+)
+
 type PremieresClient struct {
 	httpClient   *http.Client
 	conf         config.Config
 	premieresUrl string
 	now          time.Time
+	streamers    streamer.Mapping
 }
 
 func NewPremieresClient(conf config.Config) PremieresClient {
@@ -28,6 +39,7 @@ func NewPremieresClient(conf config.Config) PremieresClient {
 		conf:         conf,
 		premieresUrl: premieresUrl,
 		now:          time.Now(),
+		streamers:    streamer.NewMapping(conf.Streamers),
 	}
 }
 
@@ -49,6 +61,7 @@ func (pc PremieresClient) GetPotentiallyInterestingPremieres(lastProcessedDate s
 	}
 
 	premiereSet := make(map[string]*Premiere, 0) //used for deduplication
+	skippedSet := make(map[string]*Premiere, 0)
 
 	// Find the new releases
 	done := false
@@ -69,10 +82,11 @@ func (pc PremieresClient) GetPotentiallyInterestingPremieres(lastProcessedDate s
 			done = true
 			return
 		}
+		premiereDate := pc.parseDate(dateParts[1])
 
 		//Find the list of premieres for this date
 		s.Next().Find("tr").Each(func(i int, s *goquery.Selection) {
-			premiere := new(Premiere)
+			premiere := &Premiere{Date: premiereDate}
 
 			//Check if it's a movie
 			titleLink := s.Find("td:nth-child(2) a").First()
@@ -97,6 +111,8 @@ func (pc PremieresClient) GetPotentiallyInterestingPremieres(lastProcessedDate s
 				return //this is apparently a duplicate
 			}
 
+			premiere.Season = pc.parseSeason(s)
+
 			//Check if it's a new series
 			newImg := s.Find("td:nth-child(2) img[alt=\"new series\"]")
 			if newImg != nil && newImg.Nodes != nil {
@@ -120,14 +136,20 @@ func (pc PremieresClient) GetPotentiallyInterestingPremieres(lastProcessedDate s
 				genresRaw = strings.TrimSpace(genresRaw)
 			}
 			genreList := strings.Split(genresRaw, "/")
+			premiere.Genres = genreList
 			if !pc.conf.IsInterestingMainGenre(genreList) {
+				if len(parts) != 2 {
+					premiere.Genres = nil //the genres couldn't be found
+				}
+				skippedSet[premiere.Title] = premiere
 				return //Not an interesting genre
 			}
-			premiere.Genres = genreList
 
-			//Get streamer
+			//Get streamers and networks
 			networkRaw := s.Find("td:nth-child(3)")
-			premiere.StreamingOption = pc.getStreamer(networkRaw)
+			networkText := strings.Join(strings.Fields(networkRaw.Text()), " ")
+			premiere.StreamingOptions = pc.streamers.Detect(networkText)
+			premiere.Network = parseNetwork(networkText, premiere.StreamingOptions)
 
 			premiereSet[premiere.Title] = premiere
 		})
@@ -141,10 +163,67 @@ func (pc PremieresClient) GetPotentiallyInterestingPremieres(lastProcessedDate s
 	for _, r := range premiereSet {
 		premieres.Premieres = append(premieres.Premieres, *r)
 	}
+	for title, r := range skippedSet {
+		if _, ok := premiereSet[title]; !ok {
+			premieres.Skipped = append(premieres.Skipped, *r)
+		}
+	}
 
 	return premieres, nil
 }
 
+// parseDate turns the date heading (e.g. "June 11") into a date. The year isn't given, so it's
+// the one which puts the date closest to now.
+func (pc PremieresClient) parseDate(raw string) time.Time {
+	raw = strings.Join(strings.Fields(raw), " ")
+
+	closest := time.Time{}
+	for _, year := range []int{pc.now.Year() - 1, pc.now.Year(), pc.now.Year() + 1} {
+		date, err := time.Parse("January 2 2006", fmt.Sprintf("%s %d", raw, year))
+		if err != nil {
+			return time.Time{}
+		}
+		if closest.IsZero() || absDuration(date.Sub(pc.now)) < absDuration(closest.Sub(pc.now)) {
+			closest = date
+		}
+	}
+	return closest
+}
+
+// parseNetwork returns the name of the network from the third column, which lists the network with the air
+// time and then the streamers, e.g. "HBO 9p HBO Max". Without an air time it's a streaming premiere, so it's
+// the first of the streamers, e.g. "Hulu" for "Hulu/Disney+ (6/10)".
+func parseNetwork(text string, providers []streamer.Provider) string {
+	if loc := airTimeRegex.FindStringIndex(text); loc != nil {
+		return strings.TrimSpace(text[:loc[0]])
+	}
+	if len(providers) > 0 {
+		return string(providers[0].Name)
+	}
+	return text
+}
+
+// parseSeason finds the season number in the metacritic links of the row (e.g. /tv/sweet-magnolias/season-5/)
+func (pc PremieresClient) parseSeason(row *goquery.Selection) int {
+	season := 0
+	row.Find("a").EachWithBreak(func(i int, a *goquery.Selection) bool {
+		href, _ := a.Attr("href")
+		if m := seasonRegex.FindStringSubmatch(href); m != nil {
+			season, _ = strconv.Atoi(m[1])
+			return false
+		}
+		return true
+	})
+	return season
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
 func (pc PremieresClient) cleanTitle(t string) string {
 	t = strings.ReplaceAll(t, "Trailer2", "")
 	t = strings.ReplaceAll(t, "Trailer", "")
@@ -153,17 +232,3 @@ func (pc PremieresClient) cleanTitle(t string) string {
 	t = strings.ReplaceAll(t, "Red-band trailer", "")
 	return strings.TrimSpace(t)
 }
-
-func (pc PremieresClient) getStreamer(s *goquery.Selection) streamer.Streamer {
-	rawText := s.Text()
-	if strings.Contains(rawText, "Netflix") {
-		return streamer.Netflix
-	}
-	if strings.Contains(rawText, "Prime Video") {
-		return streamer.Amazon
-	}
-	if strings.Contains(rawText, "Disney+") {
-		return streamer.Disney
-	}
-	return streamer.None
-}
@@ -5,10 +5,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/streamer"
 )
 
 func Test_GetPotentiallyInterestingPremieres(t *testing.T) {
@@ -58,4 +60,168 @@ func Test_GetPotentiallyInterestingPremieres(t *testing.T) {
 }
 
 //Time bandits wrong genres
-//Wandavision? what?
\ No newline at end of file
+//Wandavision? what?
+func Test_GetPotentiallyInterestingPremieres_Streamers(t *testing.T) {
+	//given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		dat, err := ioutil.ReadFile("testdata/metacritic-tv-premieres.html")
+		require.NoError(t, err, "There was an error reading the test data file")
+		rw.Write(dat)
+	}))
+	defer server.Close()
+
+	conf := config.Config{MainGenres: []string{"Drama", "Comedy"}}
+	premieresClient := PremieresClient{httpClient: server.Client(), conf: conf, premieresUrl: server.URL, streamers: streamer.NewMapping(nil)}
+
+	//when
+	premieres, err := premieresClient.GetPotentiallyInterestingPremieres("June 10")
+
+	//then
+	require.NoError(t, err, "There was an error getting the premieres")
+	found := false
+	for _, p := range premieres.Premieres {
+		if p.Title == "Sweet Magnolias" {
+			found = true
+			require.Equal(t, 1, len(p.StreamingOptions))
+			assert.Equal(t, streamer.Netflix, p.StreamingOptions[0].Name)
+			assert.Equal(t, "Netflix", p.Network)
+			assert.Equal(t, 5, p.Season)
+		}
+	}
+	assert.True(t, found, "Sweet Magnolias should be in the list")
+
+	skipped := make(map[string][]string)
+	for _, p := range premieres.Skipped {
+		skipped[p.Title] = p.Genres
+	}
+	assert.Equal(t, []string{"Reality"}, skipped["Mark vs. The Mountain"], "The premieres in other genres should be listed")
+}
+
+func Test_GetPotentiallyInterestingPremieres_Networks(t *testing.T) {
+	//given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		dat, err := ioutil.ReadFile("testdata/metacritic-networks.html")
+		require.NoError(t, err, "There was an error reading the test data file")
+		rw.Write(dat)
+	}))
+	defer server.Close()
+
+	conf := config.Config{MainGenres: []string{"Drama", "Comedy"}}
+	premieresClient := PremieresClient{httpClient: server.Client(), conf: conf, premieresUrl: server.URL, streamers: streamer.NewMapping(nil)}
+
+	//when
+	premieres, err := premieresClient.GetPotentiallyInterestingPremieres("June 11")
+
+	//then
+	require.NoError(t, err, "There was an error getting the premieres")
+	byTitle := make(map[string]Premiere)
+	for _, p := range premieres.Premieres {
+		byTitle[p.Title] = p
+	}
+	require.Equal(t, 2, len(byTitle))
+
+	darkWinds := byTitle["Dark Winds"]
+	assert.Equal(t, "AMC", darkWinds.Network, "The air time and the streamers aren't part of the network")
+	assert.Equal(t, []streamer.Streamer{"AMC", "AMC+"}, providerNames(darkWinds.StreamingOptions))
+	assert.Equal(t, 3, darkWinds.Season)
+
+	theBear := byTitle["The Bear"]
+	assert.Equal(t, "Hulu", theBear.Network, "Without a network it's the first streamer")
+	assert.Equal(t, []streamer.Streamer{"Hulu", streamer.Disney}, providerNames(theBear.StreamingOptions))
+	assert.Equal(t, 4, theBear.Season)
+}
+
+func providerNames(providers []streamer.Provider) []streamer.Streamer {
+	names := make([]streamer.Streamer, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func Test_parseDate(t *testing.T) {
+	testcases := map[string]struct {
+		Raw      string
+		Now      time.Time
+		Expected time.Time
+	}{
+		"Same year": {
+			Raw:      "June 11",
+			Now:      time.Date(2020, 6, 15, 14, 0, 0, 0, time.UTC),
+			Expected: time.Date(2020, 6, 11, 0, 0, 0, 0, time.UTC),
+		},
+		"Extra spaces": {
+			Raw:      "  June   10",
+			Now:      time.Date(2020, 6, 15, 14, 0, 0, 0, time.UTC),
+			Expected: time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC),
+		},
+		"End of last year": {
+			Raw:      "December 30",
+			Now:      time.Date(2021, 1, 4, 14, 0, 0, 0, time.UTC),
+			Expected: time.Date(2020, 12, 30, 0, 0, 0, 0, time.UTC),
+		},
+		"Beginning of next year": {
+			Raw:      "January 2",
+			Now:      time.Date(2020, 12, 28, 14, 0, 0, 0, time.UTC),
+			Expected: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		"Garbage": {
+			Raw:      "tbd",
+			Now:      time.Date(2020, 6, 15, 14, 0, 0, 0, time.UTC),
+			Expected: time.Time{},
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		premieresClient := PremieresClient{now: testdata.Now}
+
+		//when
+		date := premieresClient.parseDate(testdata.Raw)
+
+		//then
+		assert.Equal(t, testdata.Expected, date, testcase)
+	}
+}
+
+func Test_parseNetwork(t *testing.T) {
+	mapping := streamer.NewMapping(nil)
+
+	testcases := map[string]struct {
+		Text     string
+		Expected string
+	}{
+		"Network with an air time and a streamer": {
+			Text:     "HBO 9p HBO Max",
+			Expected: "HBO",
+		},
+		"Air time with minutes": {
+			Text:     "BBC America 8:30p Acorn TV/AMC+",
+			Expected: "BBC America",
+		},
+		"Unknown network": {
+			Text:     "Hallmark 8p",
+			Expected: "Hallmark",
+		},
+		"Streamer only": {
+			Text:     "Netflix",
+			Expected: "Netflix",
+		},
+		"Several streamers": {
+			Text:     "Hulu/Disney+ (6/10)",
+			Expected: "Hulu",
+		},
+		"Unknown streamer": {
+			Text:     "Crackle",
+			Expected: "Crackle",
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//when
+		network := parseNetwork(testdata.Text, mapping.Detect(testdata.Text))
+
+		//then
+		assert.Equal(t, testdata.Expected, network, testcase)
+	}
+}
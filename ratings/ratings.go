@@ -0,0 +1,86 @@
+package ratings
+
+import (
+	"github.com/ynori7/tvshows/imdbexport"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/watchlist"
+)
+
+// Rating is a show which we've rated on IMDB
+type Rating struct {
+	ImdbId    string
+	Title     string
+	Rating    int //out of 10
+	Genres    []string
+	Directors []string
+}
+
+// History is our personal viewing history: the shows we rated and the ones we dropped
+type History struct {
+	ratings map[string]Rating
+	dropped watchlist.Watchlist
+}
+
+func New() History {
+	return History{
+		ratings: make(map[string]Rating),
+		dropped: watchlist.New(),
+	}
+}
+
+// Load builds the history from IMDB ratings CSV exports and lists of dropped shows. The
+// dropped lists support the same formats as the watchlist.
+func Load(files []string, droppedFiles []string) (History, error) {
+	h := New()
+
+	for _, file := range files {
+		entries, err := imdbexport.ReadFile(file)
+		if err != nil {
+			return h, err
+		}
+		for _, e := range entries {
+			if e.YourRating == 0 || !e.IsTvSeries() {
+				continue
+			}
+			h.Add(Rating{
+				ImdbId:    e.ImdbId,
+				Title:     e.Title,
+				Rating:    e.YourRating,
+				Genres:    e.Genres,
+				Directors: e.Directors,
+			})
+		}
+	}
+
+	dropped, err := watchlist.Load(droppedFiles)
+	if err != nil {
+		return h, err
+	}
+	h.dropped = dropped
+
+	return h, nil
+}
+
+func (h History) Add(r Rating) {
+	h.ratings[r.ImdbId] = r
+}
+
+// Lookup returns our rating for the show, if we rated it
+func (h History) Lookup(show tvshow.TvShow) (Rating, bool) {
+	r, ok := h.ratings[show.ImdbId]
+	return r, ok
+}
+
+// IsDropped returns true if we stopped watching the show
+func (h History) IsDropped(show tvshow.TvShow) bool {
+	return h.dropped.Contains(show)
+}
+
+// Ratings returns all of the rated shows
+func (h History) Ratings() []Rating {
+	list := make([]Rating, 0, len(h.ratings))
+	for _, r := range h.ratings {
+		list = append(list, r)
+	}
+	return list
+}
@@ -0,0 +1,29 @@
+package ratings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+func Test_Load(t *testing.T) {
+	//when
+	h, err := Load([]string{"testdata/ratings.csv"}, []string{"testdata/dropped.txt"})
+
+	//then
+	require.NoError(t, err, "There was an error loading the ratings")
+	assert.Equal(t, 3, len(h.Ratings()))
+
+	r, ok := h.Lookup(tvshow.TvShow{ImdbId: "tt0944947"})
+	assert.True(t, ok)
+	assert.Equal(t, 9, r.Rating)
+	assert.Equal(t, "Game of Thrones", r.Title)
+
+	_, ok = h.Lookup(tvshow.TvShow{ImdbId: "tt7134908"})
+	assert.False(t, ok)
+
+	assert.True(t, h.IsDropped(tvshow.TvShow{ImdbId: "tt7134908"}))
+	assert.False(t, h.IsDropped(tvshow.TvShow{ImdbId: "tt0944947"}))
+}
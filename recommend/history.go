@@ -0,0 +1,52 @@
+package recommend
+
+import (
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/ratings"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+// LikedDocuments turns the shows we rated at least the liked threshold into weighted documents.
+// The better the rating, the higher the weight. For the best rated shows (up to the lookup limit)
+// the details are fetched from IMDB so that the keywords and description can be compared as well,
+// otherwise only the genres and directors from the export are used.
+func LikedDocuments(history []ratings.Rating, client tvshow.ImdbClient, conf config.Recommendation, logger log.FieldLogger) []WeightedDocument {
+	logger = logger.WithFields(log.Fields{"Logger": "LikedDocuments"})
+
+	liked := make([]ratings.Rating, 0)
+	for _, r := range history {
+		if r.Rating >= conf.GetLikedThreshold() {
+			liked = append(liked, r)
+		}
+	}
+	sort.Slice(liked, func(i, j int) bool {
+		return liked[i].Rating > liked[j].Rating
+	})
+
+	docs := make([]WeightedDocument, 0, len(liked))
+	for i, r := range liked {
+		doc := Document{
+			Genres:   r.Genres,
+			Creators: r.Directors,
+		}
+
+		if i < conf.LookupLimit {
+			show, err := client.GetTvShowData(client.BuildTitleLink(r.ImdbId))
+			if err != nil {
+				logger.WithFields(log.Fields{"error": err, "Title": r.Title}).Warn("Error looking up liked series")
+			} else {
+				doc = DocumentFromTvShow(*show)
+			}
+		}
+
+		docs = append(docs, WeightedDocument{
+			Document: doc,
+			Weight:   float64(r.Rating - conf.GetLikedThreshold() + 1),
+		})
+	}
+
+	return docs
+}
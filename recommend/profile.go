@@ -0,0 +1,108 @@
+package recommend
+
+import (
+	"math"
+	"sync"
+)
+
+// WeightedDocument is a show from our history along with how much we liked it
+type WeightedDocument struct {
+	Document Document
+	Weight   float64
+}
+
+// Scorer returns the relevance score of a show, e.g. a Profile
+type Scorer interface {
+	Score(d Document) int
+}
+
+// Profile is the combination of all of the shows we liked. The relevance of a new show is
+// its similarity to this profile.
+type Profile struct {
+	features features
+	size     int
+}
+
+func NewProfile(liked []WeightedDocument) Profile {
+	p := Profile{
+		features: features{
+			genres:      make(Vector),
+			keywords:    make(Vector),
+			creators:    make(Vector),
+			description: make(Vector),
+		},
+	}
+
+	for _, d := range liked {
+		if d.Weight <= 0 {
+			continue
+		}
+		f := newFeatures(d.Document)
+		p.features.genres.add(f.genres, d.Weight)
+		p.features.keywords.add(f.keywords, d.Weight)
+		p.features.creators.add(f.creators, d.Weight)
+		p.features.description.add(f.description, d.Weight)
+		p.size++
+	}
+
+	return p
+}
+
+// IsEmpty returns true if there's nothing to compare with
+func (p Profile) IsEmpty() bool {
+	return p.size == 0
+}
+
+// Score returns a relevance score out of 100 for the document. Features which are missing
+// on either side don't count, so a show without keywords isn't penalized for it.
+func (p Profile) Score(d Document) int {
+	if p.IsEmpty() {
+		return 0
+	}
+
+	f := newFeatures(d)
+	parts := []struct {
+		profile Vector
+		doc     Vector
+		weight  float64
+	}{
+		{p.features.genres, f.genres, genresWeight},
+		{p.features.keywords, f.keywords, keywordsWeight},
+		{p.features.creators, f.creators, creatorsWeight},
+		{p.features.description, f.description, descriptionWeight},
+	}
+
+	total := 0.0
+	weights := 0.0
+	for _, part := range parts {
+		if len(part.profile) == 0 || len(part.doc) == 0 {
+			continue
+		}
+		total += part.weight * Cosine(part.profile, part.doc)
+		weights += part.weight
+	}
+	if weights == 0 {
+		return 0
+	}
+
+	return int(math.Round(100 * total / weights))
+}
+
+// LazyProfile builds the profile the first time a show is scored. Building it looks up the liked shows on
+// IMDB, which isn't worth it when no premiere gets that far.
+type LazyProfile struct {
+	build   func() Profile
+	once    sync.Once
+	profile Profile
+}
+
+func NewLazyProfile(build func() Profile) *LazyProfile {
+	return &LazyProfile{build: build}
+}
+
+func (l *LazyProfile) Score(d Document) int {
+	l.once.Do(func() {
+		l.profile = l.build()
+	})
+	return l.profile.Score(d)
+}
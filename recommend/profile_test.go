@@ -0,0 +1,158 @@
+package recommend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	gameOfThrones = Document{
+		Genres:      []string{"Action", "Adventure", "Drama"},
+		Keywords:    []string{"based on novel", "dragon", "politics", "queen"},
+		Creators:    []string{"David Benioff", "D.B. Weiss"},
+		Description: "Nine noble families fight for control over the lands of Westeros, while an ancient enemy returns after being dormant for millennia.",
+	}
+	houseOfTheDragon = Document{
+		Genres:      []string{"Action", "Adventure", "Drama"},
+		Keywords:    []string{"dragon", "prequel", "queen", "based on novel"},
+		Creators:    []string{"Ryan J. Condal", "George R.R. Martin"},
+		Description: "An internal succession war within House Targaryen at the height of its power, 172 years before the birth of Daenerys Targaryen.",
+	}
+	theBachelor = Document{
+		Genres:      []string{"Game-Show", "Reality-TV", "Romance"},
+		Keywords:    []string{"dating", "reality show", "rose"},
+		Creators:    []string{"Mike Fleiss"},
+		Description: "A single bachelor dates multiple women over several weeks, narrowing them down to hopefully find his true love.",
+	}
+	blackAF = Document{
+		Genres:      []string{"Comedy"},
+		Creators:    []string{"Kenya Barris"},
+		Description: "A father takes an irreverent and honest approach to parenting and relationships.",
+	}
+)
+
+func Test_Cosine(t *testing.T) {
+	testcases := map[string]struct {
+		A        Vector
+		B        Vector
+		Expected float64
+	}{
+		"Identical": {
+			A:        Vector{"drama": 1, "action": 1},
+			B:        Vector{"drama": 1, "action": 1},
+			Expected: 1,
+		},
+		"Scaled": {
+			A:        Vector{"drama": 2, "action": 2},
+			B:        Vector{"drama": 1, "action": 1},
+			Expected: 1,
+		},
+		"Nothing in common": {
+			A:        Vector{"drama": 1},
+			B:        Vector{"comedy": 1},
+			Expected: 0,
+		},
+		"Partial overlap": {
+			A:        Vector{"drama": 1, "action": 1},
+			B:        Vector{"drama": 1, "comedy": 1},
+			Expected: 0.5,
+		},
+		"Empty": {
+			A:        Vector{},
+			B:        Vector{"drama": 1},
+			Expected: 0,
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		assert.InDelta(t, testdata.Expected, Cosine(testdata.A, testdata.B), 0.0001, testcase)
+	}
+}
+
+func Test_Score(t *testing.T) {
+	//given
+	profile := NewProfile([]WeightedDocument{
+		{Document: gameOfThrones, Weight: 3},
+		{Document: blackAF, Weight: 1},
+	})
+
+	testcases := map[string]struct {
+		Document Document
+		Expected int
+	}{
+		"Same show": {
+			Document: gameOfThrones,
+			Expected: 96,
+		},
+		"Similar show": {
+			Document: houseOfTheDragon,
+			Expected: 52,
+		},
+		"Less liked show": {
+			Document: blackAF,
+			Expected: 32,
+		},
+		"Unrelated show": {
+			Document: theBachelor,
+			Expected: 1,
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//when
+		score := profile.Score(testdata.Document)
+
+		//then
+		assert.Equal(t, testdata.Expected, score, testcase)
+	}
+}
+
+func Test_LazyProfile(t *testing.T) {
+	//given
+	builds := 0
+	profile := NewLazyProfile(func() Profile {
+		builds++
+		return NewProfile([]WeightedDocument{{Document: gameOfThrones, Weight: 1}})
+	})
+
+	//then
+	assert.Equal(t, 0, builds, "Nothing was scored yet")
+
+	//when
+	first := profile.Score(gameOfThrones)
+	second := profile.Score(Document{Genres: gameOfThrones.Genres})
+
+	//then
+	assert.Equal(t, 100, first)
+	assert.Equal(t, 100, second)
+	assert.Equal(t, 1, builds)
+}
+
+func Test_Score_MissingFeaturesArentPenalized(t *testing.T) {
+	//given
+	profile := NewProfile([]WeightedDocument{{Document: gameOfThrones, Weight: 1}})
+
+	//when
+	withoutKeywords := profile.Score(Document{Genres: gameOfThrones.Genres})
+
+	//then
+	assert.Equal(t, 100, withoutKeywords)
+}
+
+func Test_Score_EmptyProfile(t *testing.T) {
+	//given
+	profile := NewProfile([]WeightedDocument{{Document: gameOfThrones, Weight: 0}})
+
+	//then
+	assert.True(t, profile.IsEmpty())
+	assert.Equal(t, 0, profile.Score(gameOfThrones))
+}
+
+func Test_tokenize(t *testing.T) {
+	//when
+	tokens := tokenize("A father takes an irreverent, honest approach to parenting & relationships.")
+
+	//then
+	assert.Equal(t, []string{"father", "takes", "irreverent", "honest", "approach", "parenting", "relationships"}, tokens)
+}
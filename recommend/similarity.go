@@ -0,0 +1,128 @@
+package recommend
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+// Vector is a sparse term frequency vector
+type Vector map[string]float64
+
+// Document holds the features of a show which are used to compare it with other shows
+type Document struct {
+	Genres      []string
+	Keywords    []string
+	Creators    []string
+	Description string
+}
+
+// DocumentFromTvShow extracts the comparable features from the show
+func DocumentFromTvShow(show tvshow.TvShow) Document {
+	return Document{
+		Genres:      show.Genres,
+		Keywords:    show.Keywords,
+		Creators:    show.Creators,
+		Description: show.Description,
+	}
+}
+
+// the relative importance of each feature
+const (
+	genresWeight      = 0.35
+	keywordsWeight    = 0.25
+	creatorsWeight    = 0.2
+	descriptionWeight = 0.2
+)
+
+type features struct {
+	genres      Vector
+	keywords    Vector
+	creators    Vector
+	description Vector
+}
+
+func newFeatures(d Document) features {
+	return features{
+		genres:      termVector(d.Genres),
+		keywords:    termVector(d.Keywords),
+		creators:    termVector(d.Creators),
+		description: termVector(tokenize(d.Description)),
+	}
+}
+
+// termVector builds a normalized vector of the terms
+func termVector(terms []string) Vector {
+	v := make(Vector)
+	for _, t := range terms {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" {
+			v[t]++
+		}
+	}
+	return v.normalize()
+}
+
+func (v Vector) norm() float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+func (v Vector) normalize() Vector {
+	n := v.norm()
+	if n == 0 {
+		return v
+	}
+	for t, x := range v {
+		v[t] = x / n
+	}
+	return v
+}
+
+// add adds the other vector scaled by the weight
+func (v Vector) add(other Vector, weight float64) {
+	for t, x := range other {
+		v[t] += x * weight
+	}
+}
+
+// Cosine returns the cosine similarity of the two vectors, from -1 to 1. It's 0 if either is empty.
+func Cosine(a Vector, b Vector) float64 {
+	na, nb := a.norm(), b.norm()
+	if na == 0 || nb == 0 {
+		return 0
+	}
+
+	dot := 0.0
+	for t, x := range a {
+		dot += x * b[t]
+	}
+	return dot / (na * nb)
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "that": true, "this": true,
+	"his": true, "her": true, "their": true, "they": true, "who": true, "into": true, "after": true,
+	"when": true, "while": true, "about": true, "are": true, "was": true, "has": true, "have": true,
+	"its": true, "series": true, "show": true, "starring": true, "one": true, "two": true, "new": true,
+}
+
+// tokenize splits the text into lower case words, leaving out short words and stop words
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, w := range words {
+		if len(w) > 2 && !stopWords[w] {
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,38 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// parser reads the five standard fields: minute, hour, day of the month, month and day of the week, as well as
+// the descriptors like @daily and @weekly
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	schedule cron.Schedule
+}
+
+// Parse reads the cron expression. An expression which never matches, e.g. "0 0 31 2 *", is an error too.
+func Parse(expr string) (Schedule, error) {
+	s, err := parser.Parse(expr)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if s.Next(time.Now()).IsZero() {
+		return Schedule{}, fmt.Errorf("cron expression %q never matches", expr)
+	}
+	return Schedule{schedule: s}, nil
+}
+
+// Next returns the first time after the given time which matches the schedule, in the location of the
+// given time. It returns the zero time if nothing matches.
+func (s Schedule) Next(after time.Time) time.Time {
+	if s.schedule == nil {
+		return time.Time{}
+	}
+	return s.schedule.Next(after)
+}
@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Next(t *testing.T) {
+	//Monday, June 8th 2020
+	now := time.Date(2020, 6, 8, 13, 30, 15, 0, time.UTC)
+
+	testcases := map[string]struct {
+		Expr     string
+		Expected time.Time
+	}{
+		"Every monday at 14:00": {
+			Expr:     "0 14 * * 1",
+			Expected: time.Date(2020, 6, 8, 14, 0, 0, 0, time.UTC),
+		},
+		"Every monday at 13:00 is next week": {
+			Expr:     "0 13 * * mon",
+			Expected: time.Date(2020, 6, 15, 13, 0, 0, 0, time.UTC),
+		},
+		"Every 15 minutes": {
+			Expr:     "*/15 * * * *",
+			Expected: time.Date(2020, 6, 8, 13, 45, 0, 0, time.UTC),
+		},
+		"Lists and ranges": {
+			Expr:     "5,10 9-11 * * *",
+			Expected: time.Date(2020, 6, 9, 9, 5, 0, 0, time.UTC),
+		},
+		"Sunday": {
+			Expr:     "0 0 * * sun",
+			Expected: time.Date(2020, 6, 14, 0, 0, 0, 0, time.UTC),
+		},
+		"Month names": {
+			Expr:     "0 0 1 jan *",
+			Expected: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"Day of month or day of week": {
+			Expr:     "0 0 10 * fri",
+			Expected: time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC),
+		},
+		"Weekly": {
+			Expr:     "@weekly",
+			Expected: time.Date(2020, 6, 14, 0, 0, 0, 0, time.UTC),
+		},
+		"Leap day": {
+			Expr:     "0 12 29 2 *",
+			Expected: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		s, err := Parse(testdata.Expr)
+		require.NoError(t, err, testcase)
+
+		//when
+		actual := s.Next(now)
+
+		//then
+		assert.Equal(t, testdata.Expected, actual, testcase)
+	}
+}
+
+func Test_Parse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "* * * foo *", "0 0 31 2 *"} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
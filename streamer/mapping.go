@@ -0,0 +1,134 @@
+package streamer
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Mapping detects the providers by their names and aliases
+type Mapping struct {
+	providers []Provider
+	aliases   []alias //sorted from longest to shortest so the most specific name wins
+}
+
+type alias struct {
+	name     string //lower case
+	provider int
+}
+
+// NewMapping builds the mapping from the default providers. Custom providers with the same
+// name as a default one replace it, others are added.
+func NewMapping(custom []Provider) Mapping {
+	m := Mapping{}
+
+	overridden := make(map[Streamer]bool, len(custom))
+	for _, p := range custom {
+		overridden[p.Name] = true
+	}
+	for _, p := range DefaultProviders {
+		if !overridden[p.Name] {
+			m.providers = append(m.providers, p)
+		}
+	}
+	for _, p := range custom {
+		if p.Type == "" {
+			p.Type = Streaming
+		}
+		m.providers = append(m.providers, p)
+	}
+
+	for i, p := range m.providers {
+		m.aliases = append(m.aliases, alias{name: strings.ToLower(string(p.Name)), provider: i})
+		for _, a := range p.Aliases {
+			m.aliases = append(m.aliases, alias{name: strings.ToLower(a), provider: i})
+		}
+	}
+	sort.SliceStable(m.aliases, func(i, j int) bool {
+		return len(m.aliases[i].name) > len(m.aliases[j].name)
+	})
+
+	return m
+}
+
+// Lookup finds the provider by its name or one of its aliases
+func (m Mapping) Lookup(name string) (Provider, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, a := range m.aliases {
+		if a.name == name {
+			return m.providers[a.provider], true
+		}
+	}
+	return Provider{}, false
+}
+
+// Detect finds all of the providers mentioned in the text, in the order they're mentioned. Names
+// only match whole words, so "AMC" doesn't match "AMC+", and longer names are preferred, so
+// "BBC America" doesn't also count as "BBC".
+func (m Mapping) Detect(text string) []Provider {
+	lower := []rune(strings.ToLower(text))
+	taken := make([]bool, len(lower))
+
+	type match struct {
+		position int
+		provider int
+	}
+	matches := make([]match, 0)
+	found := make(map[int]bool)
+
+	for _, a := range m.aliases {
+		name := []rune(a.name)
+		for i := 0; i+len(name) <= len(lower); i++ {
+			if !hasPrefixAt(lower, name, i) || !isBoundary(lower, i-1) || !isBoundary(lower, i+len(name)) {
+				continue
+			}
+			if isTaken(taken, i, i+len(name)) {
+				continue
+			}
+			for k := i; k < i+len(name); k++ {
+				taken[k] = true
+			}
+			if !found[a.provider] {
+				found[a.provider] = true
+				matches = append(matches, match{position: i, provider: a.provider})
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].position < matches[j].position
+	})
+
+	providers := make([]Provider, 0, len(matches))
+	for _, match := range matches {
+		providers = append(providers, m.providers[match.provider])
+	}
+	return providers
+}
+
+func hasPrefixAt(text []rune, prefix []rune, i int) bool {
+	for k, r := range prefix {
+		if text[i+k] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// isBoundary checks whether the character at i doesn't continue a name
+func isBoundary(text []rune, i int) bool {
+	if i < 0 || i >= len(text) {
+		return true
+	}
+	r := text[i]
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '&'
+}
+
+func isTaken(taken []bool, from int, to int) bool {
+	for i := from; i < to; i++ {
+		if taken[i] {
+			return true
+		}
+	}
+	return false
+}
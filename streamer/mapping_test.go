@@ -0,0 +1,94 @@
+package streamer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Detect(t *testing.T) {
+	//given
+	mapping := NewMapping(nil)
+
+	testcases := map[string]struct {
+		Text     string
+		Expected []Streamer
+	}{
+		"Single streamer": {
+			Text:     "Netflix",
+			Expected: []Streamer{Netflix},
+		},
+		"Network and streamer": {
+			Text:     "HBO 9p           HBO Max",
+			Expected: []Streamer{"HBO", "HBO Max"},
+		},
+		"Several streamers": {
+			Text:     "Disney XD 5p           Hulu/Disney+ (6/10)",
+			Expected: []Streamer{"Hulu", Disney},
+		},
+		"Plus isn't part of the network name": {
+			Text:     "AMC 9p           AMC+",
+			Expected: []Streamer{"AMC", "AMC+"},
+		},
+		"Longer name wins": {
+			Text:     "BBC America 8p           Acorn TV/AMC+",
+			Expected: []Streamer{"BBC America", "Acorn TV", "AMC+"},
+		},
+		"Alias": {
+			Text:     "Apple TV",
+			Expected: []Streamer{"Apple TV+"},
+		},
+		"Case insensitive": {
+			Text:     "PEACOCK",
+			Expected: []Streamer{"Peacock"},
+		},
+		"Prime alone isn't Prime Video": {
+			Text:     "Prime Time Network 8p",
+			Expected: []Streamer{},
+		},
+		"Amazon Prime": {
+			Text:     "Amazon Prime",
+			Expected: []Streamer{Amazon},
+		},
+		"Not a whole word": {
+			Text:     "Foxtel",
+			Expected: []Streamer{},
+		},
+		"Unknown": {
+			Text:     "RENT/BUY",
+			Expected: []Streamer{},
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//when
+		providers := mapping.Detect(testdata.Text)
+
+		//then
+		names := make([]Streamer, 0)
+		for _, p := range providers {
+			names = append(names, p.Name)
+		}
+		assert.Equal(t, testdata.Expected, names, testcase)
+	}
+}
+
+func Test_NewMapping_Custom(t *testing.T) {
+	//given
+	mapping := NewMapping([]Provider{
+		{Name: Netflix, Link: "https://www.netflix.com/de"},
+		{Name: "Joyn", Aliases: []string{"Joyn+"}},
+	})
+
+	//when
+	netflix, netflixOk := mapping.Lookup("netflix")
+	joyn, joynOk := mapping.Lookup("Joyn+")
+
+	//then
+	assert.True(t, netflixOk)
+	assert.Equal(t, "https://www.netflix.com/de", netflix.Link)
+	assert.True(t, joynOk)
+	assert.Equal(t, Streamer("Joyn"), joyn.Name)
+	assert.True(t, joyn.IsStreaming())
+	assert.Equal(t, []Provider{joyn}, mapping.Detect("Joyn"))
+}
@@ -1,11 +1,32 @@
 package streamer
 
+// Streamer is the name of a streaming service or TV network
 type Streamer string
 
 const (
 	None    Streamer = ""
 	Netflix Streamer = "Netflix"
-	Amazon  Streamer = "AmazonPrime"
-	Disney  Streamer = "DisneyPlus"
+	Amazon  Streamer = "Prime Video"
+	Disney  Streamer = "Disney+"
 )
 
+type ProviderType string
+
+const (
+	Streaming ProviderType = "streaming"
+	Network   ProviderType = "network" //linear TV
+)
+
+// Provider is a streaming service or TV network along with the names it goes by in the premieres list
+type Provider struct {
+	Name    Streamer
+	Type    ProviderType
+	Aliases []string `yaml:"aliases,flow"`
+	Logo    string
+	Link    string
+}
+
+// IsStreaming returns true if it's a streaming service rather than a linear TV network
+func (p Provider) IsStreaming() bool {
+	return p.Type != Network
+}
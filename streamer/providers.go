@@ -0,0 +1,49 @@
+package streamer
+
+// DefaultProviders are the known streaming services and networks. They can be overridden
+// or extended in the config.
+var DefaultProviders = []Provider{
+	//streaming services
+	{Name: Netflix, Type: Streaming, Link: "https://www.netflix.com", Logo: "https://www.netflix.com/favicon.ico"},
+	{Name: Amazon, Type: Streaming, Aliases: []string{"Amazon Prime Video", "Amazon Prime"}, Link: "https://www.primevideo.com", Logo: "https://www.primevideo.com/favicon.ico"},
+	{Name: Disney, Type: Streaming, Aliases: []string{"Disney Plus"}, Link: "https://www.disneyplus.com", Logo: "https://www.disneyplus.com/favicon.ico"},
+	{Name: "HBO Max", Type: Streaming, Aliases: []string{"Max"}, Link: "https://www.max.com", Logo: "https://www.max.com/favicon.ico"},
+	{Name: "Apple TV+", Type: Streaming, Aliases: []string{"Apple TV", "AppleTV+"}, Link: "https://tv.apple.com", Logo: "https://tv.apple.com/favicon.ico"},
+	{Name: "Hulu", Type: Streaming, Link: "https://www.hulu.com", Logo: "https://www.hulu.com/favicon.ico"},
+	{Name: "Paramount+", Type: Streaming, Aliases: []string{"Paramount Plus"}, Link: "https://www.paramountplus.com", Logo: "https://www.paramountplus.com/favicon.ico"},
+	{Name: "Peacock", Type: Streaming, Link: "https://www.peacocktv.com", Logo: "https://www.peacocktv.com/favicon.ico"},
+	{Name: "AMC+", Type: Streaming, Link: "https://www.amcplus.com", Logo: "https://www.amcplus.com/favicon.ico"},
+	{Name: "MGM+", Type: Streaming, Link: "https://www.mgmplus.com", Logo: "https://www.mgmplus.com/favicon.ico"},
+	{Name: "Acorn TV", Type: Streaming, Link: "https://acorn.tv", Logo: "https://acorn.tv/favicon.ico"},
+	{Name: "BritBox", Type: Streaming, Link: "https://www.britbox.com", Logo: "https://www.britbox.com/favicon.ico"},
+	{Name: "Shudder", Type: Streaming, Link: "https://www.shudder.com", Logo: "https://www.shudder.com/favicon.ico"},
+	{Name: "Sundance Now", Type: Streaming, Link: "https://www.sundancenow.com", Logo: "https://www.sundancenow.com/favicon.ico"},
+	{Name: "Crunchyroll", Type: Streaming, Link: "https://www.crunchyroll.com", Logo: "https://www.crunchyroll.com/favicon.ico"},
+	{Name: "Tubi", Type: Streaming, Link: "https://tubitv.com", Logo: "https://tubitv.com/favicon.ico"},
+	{Name: "The Roku Channel", Type: Streaming, Aliases: []string{"Roku Channel"}, Link: "https://therokuchannel.roku.com"},
+	{Name: "BBC iPlayer", Type: Streaming, Aliases: []string{"iPlayer"}, Link: "https://www.bbc.co.uk/iplayer", Logo: "https://www.bbc.co.uk/favicon.ico"},
+
+	//linear TV networks
+	{Name: "HBO", Type: Network, Link: "https://www.hbo.com"},
+	{Name: "BBC", Type: Network, Aliases: []string{"BBC One", "BBC Two", "BBC Three"}, Link: "https://www.bbc.co.uk"},
+	{Name: "BBC America", Type: Network, Link: "https://www.bbcamerica.com"},
+	{Name: "AMC", Type: Network, Link: "https://www.amc.com"},
+	{Name: "FX", Type: Network, Aliases: []string{"FXX"}, Link: "https://www.fxnetworks.com"},
+	{Name: "NBC", Type: Network, Link: "https://www.nbc.com"},
+	{Name: "CBS", Type: Network, Link: "https://www.cbs.com"},
+	{Name: "ABC", Type: Network, Link: "https://abc.com"},
+	{Name: "Fox", Type: Network, Link: "https://www.fox.com"},
+	{Name: "The CW", Type: Network, Aliases: []string{"CW"}, Link: "https://www.cwtv.com"},
+	{Name: "Showtime", Type: Network, Link: "https://www.sho.com"},
+	{Name: "Starz", Type: Network, Link: "https://www.starz.com"},
+	{Name: "USA", Type: Network, Aliases: []string{"USA Network"}, Link: "https://www.usanetwork.com"},
+	{Name: "Syfy", Type: Network, Link: "https://www.syfy.com"},
+	{Name: "Bravo", Type: Network, Link: "https://www.bravotv.com"},
+	{Name: "Lifetime", Type: Network, Link: "https://www.mylifetime.com"},
+	{Name: "History", Type: Network, Link: "https://www.history.com"},
+	{Name: "Discovery", Type: Network, Link: "https://www.discovery.com"},
+	{Name: "Nat Geo", Type: Network, Aliases: []string{"National Geographic"}, Link: "https://www.nationalgeographic.com/tv"},
+	{Name: "A&E", Type: Network, Link: "https://www.aetv.com"},
+	{Name: "Adult Swim", Type: Network, Link: "https://www.adultswim.com"},
+	{Name: "Comedy Central", Type: Network, Link: "https://www.cc.com"},
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"html"
-	"log"
 	"math"
 	"math/rand"
 	"net/http"
@@ -15,9 +14,11 @@ import (
 	"unicode"
 
 	"github.com/PuerkitoBio/goquery"
+	log "github.com/sirupsen/logrus"
 	"github.com/ynori7/hulksmash/anonymizer"
 	hulkhttp "github.com/ynori7/hulksmash/http"
 	"github.com/ynori7/tvshows/config"
+	"github.com/ynori7/tvshows/metrics"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
 )
@@ -27,6 +28,25 @@ const (
 	searchURI = "/find"
 )
 
+// The kinds of requests, for the metrics
+const (
+	requestSearch = "search"
+	requestTitle  = "title"
+)
+
+var (
+	imdbIdRegex = regexp.MustCompile(`tt\d+`)
+	titleRegex  = regexp.MustCompile("[^a-zA-Z0-9\\s]+")
+)
+
+// ErrNoResult means that the search didn't find the tv series
+var ErrNoResult = fmt.Errorf("no result found")
+
+// ParseImdbId extracts the IMDB ID (e.g. tt0944947) from an ID or an IMDB url
+func ParseImdbId(s string) string {
+	return imdbIdRegex.FindString(s)
+}
+
 type ImdbClient struct {
 	httpClient    *hulkhttp.ClientV2
 	reqAnonymizer anonymizer.Anonymizer
@@ -34,24 +54,23 @@ type ImdbClient struct {
 	baseUrl       string
 	titleRegex    *regexp.Regexp
 	wafCookie     string
+	logger        log.FieldLogger
+	metrics       *metrics.Metrics
 }
 
-func NewImdbClient(conf config.Config) ImdbClient {
-	reg, err := regexp.Compile("[^a-zA-Z0-9\\s]+")
-	if err != nil {
-		log.Fatal(err)
-	}
-
+func NewImdbClient(conf config.Config, logger log.FieldLogger, m *metrics.Metrics) ImdbClient {
 	client := ImdbClient{
 		httpClient:    hulkhttp.NewClientV2(),
 		reqAnonymizer: anonymizer.New(int64(rand.Int())),
 		conf:          conf,
 		baseUrl:       baseUrl,
-		titleRegex:    reg,
+		titleRegex:    titleRegex,
+		logger:        logger,
+		metrics:       m,
 	}
 
 	if wafCookie, err := fetchWafCookie(); err != nil {
-		log.Printf("warning: failed to fetch WAF cookie: %v", err)
+		logger.WithFields(log.Fields{"Logger": "NewImdbClient", "error": err}).Warn("Failed to fetch WAF cookie")
 	} else {
 		client.wafCookie = wafCookie
 	}
@@ -81,20 +100,37 @@ func fetchWafCookie() (string, error) {
 	return "", fmt.Errorf("aws-waf-token cookie not found")
 }
 
-// GetTvShowData looks up the tv show details
-func (c ImdbClient) GetTvShowData(link string) (*TvShow, error) {
-	// Request the HTML page.
-	req, err := http.NewRequest("GET", link, nil)
-	if err != nil {
-		return nil, err
-	}
+// do sends the request with the browser headers and counts it in the metrics. A network error, rate limiting or
+// a server error counts as a failed request.
+func (c ImdbClient) do(req *http.Request, kind string) (*http.Response, error) {
 	req.Header.Set("Accept-Language", "en-US")
 	req.Header.Set("Referer", "https://www.imdb.com")
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/110.0.0.0 Safari/537.36")
 	if c.wafCookie != "" {
 		req.Header.Set("Cookie", "aws-waf-token="+c.wafCookie)
 	}
+
 	res, err := c.httpClient.Do(req)
+	if err != nil {
+		c.metrics.ImdbRequest(kind, err)
+		return nil, err
+	}
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500 {
+		c.metrics.ImdbRequest(kind, fmt.Errorf("status code error: %d %s", res.StatusCode, res.Status))
+	} else {
+		c.metrics.ImdbRequest(kind, nil)
+	}
+	return res, nil //the caller reports the status code
+}
+
+// GetTvShowData looks up the tv show details
+func (c ImdbClient) GetTvShowData(link string) (*TvShow, error) {
+	// Request the HTML page.
+	req, err := http.NewRequest("GET", link, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.do(req, requestTitle)
 	if err != nil {
 		return nil, err
 	}
@@ -131,12 +167,52 @@ func (c ImdbClient) GetTvShowData(link string) (*TvShow, error) {
 		}
 	}
 
+	if tvShow.KeywordsRaw != "" {
+		for _, k := range strings.Split(tvShow.KeywordsRaw, ",") {
+			tvShow.Keywords = append(tvShow.Keywords, html.UnescapeString(strings.TrimSpace(k)))
+		}
+	}
+
+	tvShow.Creators = parseCreators(tvShow.CreatorsRaw)
+
 	tvShow.Link = link
+	tvShow.ImdbId = ParseImdbId(tvShow.Url)
+	if tvShow.ImdbId == "" {
+		tvShow.ImdbId = ParseImdbId(link)
+	}
 	tvShow.Score = c.calculateScore(tvShow.Rating.AverageRating.String(), tvShow.Rating.RatingCount)
 
 	return tvShow, nil
 }
 
+// parseCreators returns the names of the people who created the show. Companies don't have a name.
+func parseCreators(raw interface{}) []string {
+	var list []interface{}
+	switch c := raw.(type) {
+	case []interface{}:
+		list = c
+	case map[string]interface{}:
+		list = []interface{}{c}
+	}
+
+	creators := make([]string, 0)
+	for _, item := range list {
+		creator, ok := item.(map[string]interface{})
+		if !ok || creator["@type"] != "Person" {
+			continue
+		}
+		if name, ok := creator["name"].(string); ok && name != "" {
+			creators = append(creators, html.UnescapeString(name))
+		}
+	}
+	return creators
+}
+
+// BuildTitleLink returns the IMDB url for the given IMDB ID
+func (c ImdbClient) BuildTitleLink(imdbId string) string {
+	return fmt.Sprintf("%s/title/%s/", c.baseUrl, imdbId)
+}
+
 // SearchForTvSeriesTitle returns the IMDB url for the title
 func (c ImdbClient) SearchForTvSeriesTitle(searchTitle string) (string, error) {
 	// Request the HTML page.
@@ -144,13 +220,7 @@ func (c ImdbClient) SearchForTvSeriesTitle(searchTitle string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Accept-Language", "en-US")
-	req.Header.Set("Referer", "https://www.imdb.com")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/110.0.0.0 Safari/537.36")
-	if c.wafCookie != "" {
-		req.Header.Set("Cookie", "aws-waf-token="+c.wafCookie)
-	}
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, requestSearch)
 	if err != nil {
 		return "", err
 	}
@@ -188,7 +258,7 @@ func (c ImdbClient) SearchForTvSeriesTitle(searchTitle string) (string, error) {
 	})
 
 	if len(potentialResults) == 0 {
-		return "", fmt.Errorf("no result found")
+		return "", ErrNoResult
 	}
 
 	//find the one with the most recent year
@@ -2,23 +2,38 @@ package tvshow
 
 import (
 	"encoding/json"
+	"time"
+
 	"github.com/ynori7/tvshows/streamer"
 )
 
 type TvShow struct {
-	Title           string `json:"name"`
-	Type            string `json:"@type"`
-	Link            string
-	Image           string `json:"image"`
-	Genres          []string
-	GenresRaw       interface{} `json:"genre"` //sometimes it's a string and sometimes it's a list of strings
-	Rating          Rating      `json:"aggregateRating"`
-	Description     string      `json:"description"`
-	Created         string      `json:"datePublished"`
-	AgeRating       string      `json:"contentRating"`
-	Score           int
-	StreamingOption streamer.Streamer
-	IsNewSeries     bool
+	Title            string `json:"name"`
+	Type             string `json:"@type"`
+	Url              string `json:"url"`
+	ImdbId           string
+	Link             string
+	Image            string `json:"image"`
+	Genres           []string
+	GenresRaw        interface{} `json:"genre"` //sometimes it's a string and sometimes it's a list of strings
+	Keywords         []string
+	KeywordsRaw      string `json:"keywords"` //comma separated
+	Creators         []string
+	CreatorsRaw      interface{} `json:"creator"` //sometimes it's an object and sometimes it's a list of objects
+	Rating           Rating      `json:"aggregateRating"`
+	Description      string      `json:"description"`
+	Created          string      `json:"datePublished"`
+	AgeRating        string      `json:"contentRating"`
+	Score            int
+	RelevanceScore   int //similarity to the shows we liked, out of 100
+	StreamingOptions []streamer.Provider
+	IsNewSeries      bool
+	PremiereDate     time.Time //zero if unknown
+	Network          string    //the network or streamer as it's listed with the premiere
+	Season           int       //the premiering season, zero if unknown
+	OnWatchlist      bool
+	PersonalRating   int  //our own rating out of 10, 0 if we haven't rated it
+	IsPromoted       bool //we rated it highly, so it goes to the top
 }
 
 type Rating struct {
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/ynori7/tvshows/config"
@@ -22,7 +23,7 @@ func Test_Search(t *testing.T) {
 	defer server.Close()
 
 	conf := config.Config{MainGenres: []string{"Drama", "Comedy"}}
-	imdbClient := NewImdbClient(conf)
+	imdbClient := NewImdbClient(conf, log.StandardLogger(), nil)
 	imdbClient.httpClient = hulkhttp.NewClientV2ForTests(server.Client().Transport)
 	imdbClient.baseUrl = server.URL
 
@@ -44,7 +45,7 @@ func Test_Search_TitleNotExactMatch(t *testing.T) {
 	defer server.Close()
 
 	conf := config.Config{MainGenres: []string{"Drama", "Comedy"}}
-	imdbClient := NewImdbClient(conf)
+	imdbClient := NewImdbClient(conf, log.StandardLogger(), nil)
 	imdbClient.httpClient = hulkhttp.NewClientV2ForTests(server.Client().Transport)
 	imdbClient.baseUrl = server.URL
 
@@ -66,7 +67,7 @@ func Test_Search_TitleHasAccent(t *testing.T) {
 	defer server.Close()
 
 	conf := config.Config{MainGenres: []string{"Drama", "Comedy"}}
-	imdbClient := NewImdbClient(conf)
+	imdbClient := NewImdbClient(conf, log.StandardLogger(), nil)
 	imdbClient.httpClient = hulkhttp.NewClientV2ForTests(server.Client().Transport)
 	imdbClient.baseUrl = server.URL
 
@@ -88,7 +89,7 @@ func Test_Search_TitleNonUnique(t *testing.T) {
 	defer server.Close()
 
 	conf := config.Config{MainGenres: []string{"Drama", "Comedy"}}
-	imdbClient := NewImdbClient(conf)
+	imdbClient := NewImdbClient(conf, log.StandardLogger(), nil)
 	imdbClient.httpClient = hulkhttp.NewClientV2ForTests(server.Client().Transport)
 	imdbClient.baseUrl = server.URL
 
@@ -108,7 +109,7 @@ func Test_fuzzifyTitle(t *testing.T) {
 		"Manhunt: Deadly Games":        "manhunt deadly games",
 	}
 
-	imdbClient := NewImdbClient(config.Config{})
+	imdbClient := NewImdbClient(config.Config{}, log.StandardLogger(), nil)
 
 	for testcase, expected := range testdata {
 		//when
@@ -118,3 +119,16 @@ func Test_fuzzifyTitle(t *testing.T) {
 		assert.Equal(t, expected, actual)
 	}
 }
+
+func Test_ParseImdbId(t *testing.T) {
+	testcases := map[string]string{
+		"tt0944947":                               "tt0944947",
+		"/title/tt0944947/":                       "tt0944947",
+		"https://www.imdb.com/title/tt7134908/?a": "tt7134908",
+		"Game of Thrones":                         "",
+	}
+
+	for testcase, expected := range testcases {
+		assert.Equal(t, expected, ParseImdbId(testcase), testcase)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/ynori7/tvshows/config"
@@ -32,6 +33,9 @@ func Test_GetTvShowData(t *testing.T) {
 	assert.Equal(t, server.URL, tvShow.Link)
 	assert.Equal(t, 3, len(tvShow.Genres))
 	assert.Equal(t, "Game of Thrones", tvShow.Title)
+	assert.Equal(t, "tt0944947", tvShow.ImdbId)
+	assert.Equal(t, []string{"based on novel", "dragon", "politics", "nudity", "queen"}, tvShow.Keywords)
+	assert.Equal(t, []string{"David Benioff", "D.B. Weiss"}, tvShow.Creators)
 	assert.Equal(t, 1865597, tvShow.Rating.RatingCount)
 }
 
@@ -55,6 +59,7 @@ func Test_GetTvShowData_GenresNotList(t *testing.T) {
 	assert.Equal(t, server.URL, tvShow.Link)
 	assert.Equal(t, 1, len(tvShow.Genres))
 	assert.Equal(t, "#BlackAF", tvShow.Title)
+	assert.Equal(t, []string{"Kenya Barris"}, tvShow.Creators)
 	assert.Equal(t, 1516, tvShow.Rating.RatingCount)
 }
 
@@ -92,7 +97,7 @@ func Test_calculateScore(t *testing.T) {
 		},
 	}
 
-	client := NewImdbClient(config.Config{})
+	client := NewImdbClient(config.Config{}, log.StandardLogger(), nil)
 
 	for testcase, testdata := range testcases {
 		//when
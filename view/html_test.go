@@ -0,0 +1,99 @@
+package view
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func Test_ExecuteEmailTemplate(t *testing.T) {
+	report := testReport
+	report.Appendix = true
+	report.FilteredOut = []FilteredTvShow{
+		{Title: "Sweet Magnolias", Code: "score_too_low", Reason: "score is too low: 35", Score: 35, Link: "https://www.imdb.com/title/tt10240086/"},
+		{Title: "The Bachelor", Code: "genre_mismatch", Reason: "not an interesting genre: Reality"},
+	}
+
+	testcases := map[string]struct {
+		Theme  string
+		Golden string
+	}{
+		"Light": {
+			Theme:  ThemeLight,
+			Golden: "testdata/email-light.golden.html",
+		},
+		"Dark": {
+			Theme:  ThemeDark,
+			Golden: "testdata/email-dark.golden.html",
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		templates, err := NewTemplates("", testdata.Theme)
+		require.NoError(t, err, testcase)
+
+		//when
+		out, err := NewHtmlTemplate(report, templates).ExecuteEmailTemplate()
+
+		//then
+		require.NoError(t, err, testcase)
+		if *update {
+			require.NoError(t, ioutil.WriteFile(testdata.Golden, []byte(out), 0644), testcase)
+		}
+		golden, err := ioutil.ReadFile(testdata.Golden)
+		require.NoError(t, err, "There was an error reading the golden file", testcase)
+		assert.Equal(t, string(golden), out, testcase)
+
+		//nothing which mail clients strip or block
+		for _, unsafe := range []string{"<style", "float", "<div", "background: url", "title_overview_sprite"} {
+			assert.NotContains(t, out, unsafe, testcase)
+		}
+	}
+}
+
+func Test_ExecuteEmailTemplate_InlineImages(t *testing.T) {
+	//given
+	report := testReport.WithImages(func(show tvshow.TvShow) string {
+		if show.Title == "Game of Thrones" {
+			return "cid:poster-tt0944947"
+		}
+		return show.Image
+	})
+
+	//when
+	out, err := NewHtmlTemplate(report, defaultTemplates(t)).ExecuteEmailTemplate()
+
+	//then
+	require.NoError(t, err)
+	assert.Contains(t, out, `src="cid:poster-tt0944947"`)
+	assert.Equal(t, "https://m.media-amazon.com/images/M/got.jpg", testReport.Sections[0].Shows[0].Image, "The original report shouldn't change")
+}
+
+func Test_ExecuteEmptyTemplate(t *testing.T) {
+	//given
+	report := Report{
+		Title:       "TV Shows",
+		StartDate:   "2020-06-01",
+		EndDate:     "2020-06-07",
+		Run:         RunInfo{PremieresScraped: 12},
+		FilteredOut: []FilteredTvShow{{Title: "Mark vs. The Mountain", Code: "genre_mismatch", Reason: "not an interesting genre"}},
+		Appendix:    true,
+	}
+
+	//when
+	out, err := NewHtmlTemplate(report, defaultTemplates(t)).ExecuteEmptyTemplate()
+
+	//then
+	require.NoError(t, err)
+	assert.Contains(t, out, "Nothing interesting this week")
+	assert.Contains(t, out, "None of the 12 premieres from 2020-06-01 through 2020-06-07")
+	assert.Contains(t, out, "Mark vs. The Mountain")
+	assert.NotContains(t, out, "<style")
+}
@@ -0,0 +1,151 @@
+package view
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const inlineAttr = "data-inline-css"
+
+var cssCommentRegex = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+type cssRule struct {
+	selector     string
+	declarations []string
+	specificity  int
+	order        int
+}
+
+// InlineCss moves the rules of the <style> blocks into the style attributes of the elements they
+// match, since many mail clients strip the style blocks. Rules are applied by specificity and then
+// by order, and the declarations which were already in a style attribute win. At-rules and
+// selectors with pseudo-classes can't be inlined and are dropped.
+func InlineCss(document string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(document))
+	if err != nil {
+		return "", err
+	}
+
+	css := ""
+	doc.Find("style").Each(func(i int, s *goquery.Selection) {
+		css += s.Text() + "\n"
+	}).Remove()
+
+	rules := parseCss(css)
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].specificity != rules[j].specificity {
+			return rules[i].specificity < rules[j].specificity
+		}
+		return rules[i].order < rules[j].order
+	})
+
+	for _, r := range rules {
+		doc.Find(r.selector).Each(func(i int, s *goquery.Selection) {
+			existing, _ := s.Attr(inlineAttr)
+			s.SetAttr(inlineAttr, existing+strings.Join(r.declarations, ";")+";")
+		})
+	}
+
+	doc.Find("[" + inlineAttr + "]").Each(func(i int, s *goquery.Selection) {
+		inlined, _ := s.Attr(inlineAttr)
+		style, _ := s.Attr("style")
+		s.SetAttr("style", mergeDeclarations(inlined+";"+style))
+		s.RemoveAttr(inlineAttr)
+	})
+
+	return doc.Html()
+}
+
+// parseCss splits the stylesheet into a rule per selector
+func parseCss(css string) []cssRule {
+	css = cssCommentRegex.ReplaceAllString(css, "")
+
+	rules := make([]cssRule, 0)
+	for len(css) > 0 {
+		open := strings.Index(css, "{")
+		if open < 0 {
+			break
+		}
+		prelude := strings.TrimSpace(css[:open])
+
+		//find the matching brace, skipping over the blocks of at-rules like @media
+		depth, end := 0, -1
+		for i := open; i < len(css) && end < 0; i++ {
+			switch css[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end < 0 {
+			break
+		}
+		body := css[open+1 : end]
+		css = css[end+1:]
+
+		if strings.HasPrefix(prelude, "@") {
+			continue
+		}
+		declarations := make([]string, 0)
+		for _, d := range strings.Split(body, ";") {
+			if d = strings.Join(strings.Fields(d), " "); d != "" {
+				declarations = append(declarations, d)
+			}
+		}
+		for _, selector := range strings.Split(prelude, ",") {
+			selector = strings.TrimSpace(selector)
+			if selector == "" || strings.Contains(selector, ":") {
+				continue
+			}
+			rules = append(rules, cssRule{
+				selector:     selector,
+				declarations: declarations,
+				specificity:  specificity(selector),
+				order:        len(rules),
+			})
+		}
+	}
+	return rules
+}
+
+// specificity weighs the ids, classes and elements of a simple selector
+func specificity(selector string) int {
+	score := 0
+	for _, part := range strings.Fields(strings.NewReplacer(">", " ", "+", " ", "~", " ").Replace(selector)) {
+		score += 100*strings.Count(part, "#") + 10*(strings.Count(part, ".")+strings.Count(part, "["))
+		if part[0] != '#' && part[0] != '.' && part[0] != '[' && part[0] != '*' {
+			score++
+		}
+	}
+	return score
+}
+
+// mergeDeclarations keeps the last value of each property, in the order the properties first appeared
+func mergeDeclarations(style string) string {
+	properties := make([]string, 0)
+	values := make(map[string]string)
+	for _, d := range strings.Split(style, ";") {
+		parts := strings.SplitN(d, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		property := strings.ToLower(strings.TrimSpace(parts[0]))
+		if _, ok := values[property]; !ok {
+			properties = append(properties, property)
+		}
+		values[property] = strings.TrimSpace(parts[1])
+	}
+
+	declarations := make([]string, 0, len(properties))
+	for _, p := range properties {
+		declarations = append(declarations, p+": "+values[p])
+	}
+	return strings.Join(declarations, "; ")
+}
@@ -0,0 +1,35 @@
+package view
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_InlineCss(t *testing.T) {
+	//given
+	document := `<html><head><style>
+		/* a comment */
+		td { color: #333; font-size: 10px }
+		.title, h1 { font-size: 18px; }
+		td.title { color: #000; }
+		a:visited { color: red; }
+		@media (max-width: 600px) { td { width: 100%; } }
+	</style></head><body><table><tr>
+		<td class="title" style="font-size: 20px">Title</td>
+		<td>Other</td>
+	</tr></table><h1>Heading</h1></body></html>`
+
+	//when
+	out, err := InlineCss(document)
+
+	//then
+	require.NoError(t, err)
+	assert.NotContains(t, out, "<style>")
+	assert.Contains(t, out, `<td class="title" style="color: #000; font-size: 20px">Title</td>`)
+	assert.Contains(t, out, `<td style="color: #333; font-size: 10px">Other</td>`)
+	assert.Contains(t, out, `<h1 style="font-size: 18px">Heading</h1>`)
+	assert.NotContains(t, out, "red")
+	assert.NotContains(t, out, "100%")
+}
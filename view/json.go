@@ -0,0 +1,246 @@
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+// JsonVersion is increased whenever the structure of the JSON document changes incompatibly
+const JsonVersion = 1
+
+type JsonTemplate struct {
+	Report Report
+}
+
+func NewJsonTemplate(report Report) JsonTemplate {
+	return JsonTemplate{
+		Report: report,
+	}
+}
+
+type jsonDocument struct {
+	Version     int                  `json:"version"`
+	Title       string               `json:"title"`
+	StartDate   string               `json:"start_date"`
+	EndDate     string               `json:"end_date"`
+	Run         jsonRun              `json:"run"`
+	Sections    []jsonSection        `json:"sections"`
+	FilteredOut []jsonFilteredTvShow `json:"filtered_out"`
+}
+
+type jsonSection struct {
+	Id     string       `json:"id"`
+	Title  string       `json:"title"`
+	Series []JsonTvShow `json:"series"`
+}
+
+type jsonRun struct {
+	StartedAt        time.Time `json:"started_at"`
+	FinishedAt       time.Time `json:"finished_at"`
+	DurationSeconds  float64   `json:"duration_seconds"`
+	PremieresScraped int       `json:"premieres_scraped"`
+}
+
+// JsonTvShow is a show in the JSON format, also used by the API
+type JsonTvShow struct {
+	Title            string         `json:"title"`
+	Type             string         `json:"type"`
+	ImdbId           string         `json:"imdb_id"`
+	Link             string         `json:"link"`
+	Image            string         `json:"image"`
+	Genres           []string       `json:"genres"`
+	Keywords         []string       `json:"keywords"`
+	Creators         []string       `json:"creators"`
+	AverageRating    float64        `json:"average_rating"`
+	RatingCount      int            `json:"rating_count"`
+	Description      string         `json:"description"`
+	Created          string         `json:"created"`
+	AgeRating        string         `json:"age_rating"`
+	Score            int            `json:"score"`
+	RelevanceScore   int            `json:"relevance_score"`
+	StreamingOptions []JsonProvider `json:"streaming_options"`
+	IsNewSeries      bool           `json:"is_new_series"`
+	PremiereDate     string         `json:"premiere_date,omitempty"` //yyyy-mm-dd
+	Network          string         `json:"network,omitempty"`
+	Season           int            `json:"season,omitempty"`
+	OnWatchlist      bool           `json:"on_watchlist"`
+	PersonalRating   int            `json:"personal_rating"`
+	IsPromoted       bool           `json:"is_promoted"`
+}
+
+// JsonProvider is a streaming service or network in the JSON format
+type JsonProvider struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Link string `json:"link,omitempty"`
+	Logo string `json:"logo,omitempty"`
+}
+
+type jsonFilteredTvShow struct {
+	Title  string `json:"title"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+	Score  int    `json:"score,omitempty"`
+	Link   string `json:"link,omitempty"`
+}
+
+func (j JsonTemplate) ExecuteJsonTemplate() (string, error) {
+	r := j.Report
+	doc := jsonDocument{
+		Version:   JsonVersion,
+		Title:     r.Title,
+		StartDate: r.StartDate,
+		EndDate:   r.EndDate,
+		Run: jsonRun{
+			StartedAt:        r.Run.StartedAt,
+			FinishedAt:       r.Run.FinishedAt,
+			DurationSeconds:  r.Run.FinishedAt.Sub(r.Run.StartedAt).Seconds(),
+			PremieresScraped: r.Run.PremieresScraped,
+		},
+		Sections:    make([]jsonSection, 0, len(r.Sections)),
+		FilteredOut: make([]jsonFilteredTvShow, 0, len(r.FilteredOut)),
+	}
+	for _, s := range r.Sections {
+		doc.Sections = append(doc.Sections, jsonSection{Id: s.Id, Title: s.Title, Series: ToJsonTvShows(s.Shows)})
+	}
+	for _, f := range r.FilteredOut {
+		doc.FilteredOut = append(doc.FilteredOut, jsonFilteredTvShow{Title: f.Title, Code: f.Code, Reason: f.Reason, Score: f.Score, Link: f.Link})
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func ToJsonTvShows(shows []tvshow.TvShow) []JsonTvShow {
+	list := make([]JsonTvShow, 0, len(shows))
+	for _, s := range shows {
+		rating, _ := s.Rating.AverageRating.Float64()
+		list = append(list, JsonTvShow{
+			Title:            s.Title,
+			Type:             s.Type,
+			ImdbId:           s.ImdbId,
+			Link:             s.Link,
+			Image:            s.Image,
+			Genres:           nonNil(s.Genres),
+			Keywords:         nonNil(s.Keywords),
+			Creators:         nonNil(s.Creators),
+			AverageRating:    rating,
+			RatingCount:      s.Rating.RatingCount,
+			Description:      s.Description,
+			Created:          s.Created,
+			AgeRating:        s.AgeRating,
+			Score:            s.Score,
+			RelevanceScore:   s.RelevanceScore,
+			StreamingOptions: ToJsonProviders(s.StreamingOptions),
+			IsNewSeries:      s.IsNewSeries,
+			PremiereDate:     formatDate(s.PremiereDate),
+			Network:          s.Network,
+			Season:           s.Season,
+			OnWatchlist:      s.OnWatchlist,
+			PersonalRating:   s.PersonalRating,
+			IsPromoted:       s.IsPromoted,
+		})
+	}
+	return list
+}
+
+func ToJsonProviders(providers []streamer.Provider) []JsonProvider {
+	list := make([]JsonProvider, 0, len(providers))
+	for _, p := range providers {
+		list = append(list, JsonProvider{Name: string(p.Name), Type: string(p.Type), Link: p.Link, Logo: p.Logo})
+	}
+	return list
+}
+
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// ParseJsonReport reads a report which was saved in the JSON format, e.g. from the archive
+func ParseJsonReport(data []byte) (Report, error) {
+	var doc jsonDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Report{}, err
+	}
+	if doc.Version != JsonVersion {
+		return Report{}, fmt.Errorf("unsupported version of the json report: %d", doc.Version)
+	}
+
+	r := Report{
+		Title:     doc.Title,
+		StartDate: doc.StartDate,
+		EndDate:   doc.EndDate,
+		Run: RunInfo{
+			StartedAt:        doc.Run.StartedAt,
+			FinishedAt:       doc.Run.FinishedAt,
+			PremieresScraped: doc.Run.PremieresScraped,
+		},
+		Sections:    make([]Section, 0, len(doc.Sections)),
+		FilteredOut: make([]FilteredTvShow, 0, len(doc.FilteredOut)),
+	}
+	for _, s := range doc.Sections {
+		r.Sections = append(r.Sections, Section{Id: s.Id, Title: s.Title, Shows: fromJsonTvShows(s.Series)})
+	}
+	for _, f := range doc.FilteredOut {
+		r.FilteredOut = append(r.FilteredOut, FilteredTvShow{Title: f.Title, Code: f.Code, Reason: f.Reason, Score: f.Score, Link: f.Link})
+	}
+	return r, nil
+}
+
+func fromJsonTvShows(list []JsonTvShow) []tvshow.TvShow {
+	shows := make([]tvshow.TvShow, 0, len(list))
+	for _, s := range list {
+		show := tvshow.TvShow{
+			Title:            s.Title,
+			Type:             s.Type,
+			ImdbId:           s.ImdbId,
+			Link:             s.Link,
+			Image:            s.Image,
+			Genres:           s.Genres,
+			Keywords:         s.Keywords,
+			Creators:         s.Creators,
+			Description:      s.Description,
+			Created:          s.Created,
+			AgeRating:        s.AgeRating,
+			Score:            s.Score,
+			RelevanceScore:   s.RelevanceScore,
+			StreamingOptions: make([]streamer.Provider, 0, len(s.StreamingOptions)),
+			IsNewSeries:      s.IsNewSeries,
+			Network:          s.Network,
+			Season:           s.Season,
+			OnWatchlist:      s.OnWatchlist,
+			PersonalRating:   s.PersonalRating,
+			IsPromoted:       s.IsPromoted,
+		}
+		if s.AverageRating > 0 {
+			show.Rating.AverageRating = json.Number(strconv.FormatFloat(s.AverageRating, 'f', -1, 64))
+		}
+		show.Rating.RatingCount = s.RatingCount
+		if s.PremiereDate != "" {
+			show.PremiereDate, _ = time.Parse("2006-01-02", s.PremiereDate)
+		}
+		for _, p := range s.StreamingOptions {
+			show.StreamingOptions = append(show.StreamingOptions, streamer.Provider{Name: streamer.Streamer(p.Name), Type: streamer.ProviderType(p.Type), Link: p.Link, Logo: p.Logo})
+		}
+		shows = append(shows, show)
+	}
+	return shows
+}
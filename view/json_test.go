@@ -0,0 +1,115 @@
+package view
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+func Test_ExecuteJsonTemplate(t *testing.T) {
+	//given
+	startedAt := time.Date(2020, 6, 15, 14, 0, 0, 0, time.UTC)
+	report := Report{
+		Title:     "my-conf",
+		StartDate: "June 4",
+		EndDate:   "June 11",
+		Run: RunInfo{
+			StartedAt:        startedAt,
+			FinishedAt:       startedAt.Add(90 * time.Second),
+			PremieresScraped: 10,
+		},
+		Sections: []Section{{Id: SectionNew, Title: "New Series", Shows: []tvshow.TvShow{{
+			Title:            "#BlackAF",
+			ImdbId:           "tt10311562",
+			Genres:           []string{"Comedy"},
+			Rating:           tvshow.Rating{AverageRating: "6.6", RatingCount: 1516},
+			Score:            39,
+			StreamingOptions: []streamer.Provider{{Name: streamer.Netflix, Type: streamer.Streaming}},
+			IsNewSeries:      true,
+			PremiereDate:     time.Date(2020, 6, 11, 0, 0, 0, 0, time.UTC),
+			Network:          "Netflix",
+			Season:           1,
+		}}}},
+		FilteredOut: []FilteredTvShow{{Title: "The Bachelor", Code: "score_too_low", Reason: "score is too low: 27", Score: 27}},
+	}
+
+	//when
+	out, err := NewJsonTemplate(report).ExecuteJsonTemplate()
+
+	//then
+	require.NoError(t, err, "There was an error generating the json")
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &doc), "The output should be valid json")
+	assert.Equal(t, float64(JsonVersion), doc["version"])
+	assert.Equal(t, "June 4", doc["start_date"])
+	assert.Equal(t, 90.0, doc["run"].(map[string]interface{})["duration_seconds"])
+
+	sections := doc["sections"].([]interface{})
+	require.Equal(t, 1, len(sections))
+	section := sections[0].(map[string]interface{})
+	assert.Equal(t, "new", section["id"])
+	assert.Equal(t, "New Series", section["title"])
+	newSeries := section["series"].([]interface{})
+	require.Equal(t, 1, len(newSeries))
+	show := newSeries[0].(map[string]interface{})
+	assert.Equal(t, "tt10311562", show["imdb_id"])
+	assert.Equal(t, 6.6, show["average_rating"])
+	assert.Equal(t, "2020-06-11", show["premiere_date"])
+	assert.Equal(t, "Netflix", show["network"])
+	assert.Equal(t, 1.0, show["season"])
+	assert.Equal(t, "Netflix", show["streaming_options"].([]interface{})[0].(map[string]interface{})["name"])
+
+	filtered := doc["filtered_out"].([]interface{})
+	assert.Equal(t, "The Bachelor", filtered[0].(map[string]interface{})["title"])
+	assert.Equal(t, "score_too_low", filtered[0].(map[string]interface{})["code"])
+	assert.Equal(t, 27.0, filtered[0].(map[string]interface{})["score"])
+}
+
+func Test_ParseJsonReport(t *testing.T) {
+	//given
+	out, err := NewJsonTemplate(testReport).ExecuteJsonTemplate()
+	require.NoError(t, err)
+
+	//when
+	report, err := ParseJsonReport([]byte(out))
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, testReport.Title, report.Title)
+	assert.Equal(t, testReport.StartDate, report.StartDate)
+	assert.Equal(t, len(testReport.FilteredOut), len(report.FilteredOut))
+	require.Equal(t, len(testReport.Sections), len(report.Sections))
+	for i, section := range testReport.Sections {
+		assert.Equal(t, section.Id, report.Sections[i].Id)
+		require.Equal(t, len(section.Shows), len(report.Sections[i].Shows))
+		for j, show := range section.Shows {
+			parsed := report.Sections[i].Shows[j]
+			assert.Equal(t, show.Title, parsed.Title)
+			assert.Equal(t, show.Rating, parsed.Rating)
+			assert.Equal(t, show.StreamingOptions, parsed.StreamingOptions)
+			assert.True(t, show.PremiereDate.Equal(parsed.PremiereDate), show.Title)
+			assert.Equal(t, show.Season, parsed.Season)
+		}
+	}
+
+	//the html renders the same as before
+	expected, err := NewHtmlTemplate(testReport, defaultTemplates(t)).ExecuteHtmlTemplate()
+	require.NoError(t, err)
+	actual, err := NewHtmlTemplate(report, defaultTemplates(t)).ExecuteHtmlTemplate()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ParseJsonReport_UnsupportedVersion(t *testing.T) {
+	//when
+	_, err := ParseJsonReport([]byte(`{"version": 2, "title": "my-conf"}`))
+
+	//then
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package view
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/ynori7/tvshows/streamer"
+)
+
+type MarkdownTemplate struct {
+	Report
+}
+
+func NewMarkdownTemplate(report Report) MarkdownTemplate {
+	return MarkdownTemplate{
+		Report: report,
+	}
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "#", `\#`, "<", `\<`, ">", `\>`, "|", `\|`,
+)
+
+func (m MarkdownTemplate) ExecuteMarkdownTemplate() (string, error) {
+	t, err := template.New("markdown").
+		Funcs(template.FuncMap{
+			"escape":       markdownEscaper.Replace,
+			"genres":       func(genres []string) string { return markdownEscaper.Replace(strings.Join(genres, ", ")) },
+			"formatNumber": formatNumber,
+			"premiere":     premiereLine,
+			"streaming": func(providers []streamer.Provider) []streamer.Provider {
+				return filterProviders(providers, true)
+			},
+			"networks": func(providers []streamer.Provider) []streamer.Provider {
+				return filterProviders(providers, false)
+			},
+		}).
+		Parse(markdownTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, m); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+const markdownTemplate = `# Premieres from {{ escape .StartDate }} through {{ escape .EndDate }}
+{{ range .Sections }}
+## {{ .Title }}
+{{ range .Shows }}
+### [{{ escape .Title }}]({{ .Link }})
+
+{{ if .Image }}![{{ escape .Title }} Poster]({{ .Image }})
+
+{{ end -}}
+**{{ .Rating.AverageRating }}**/10 ({{ formatNumber .Rating.RatingCount }} ratings) · Score **{{ .Score }}**/100
+{{- if .RelevanceScore }} · Relevance **{{ .RelevanceScore }}**/100{{ end }}
+{{- if .Genres }}  
+*{{ genres .Genres }}*{{ end }}
+{{- with premiere . }}  
+{{ escape . }}{{ end }}
+{{- if .PersonalRating }}  
+You rated it {{ .PersonalRating }}/10{{ end }}
+{{ if .Description }}
+{{ escape .Description }}
+{{ end }}
+{{- with streaming .StreamingOptions }}
+**Available on** {{ range $j, $p := . }}{{ if $j }}, {{ end }}{{ template "provider" $p }}{{ end }}  
+{{- end }}
+{{- with networks .StreamingOptions }}
+**Airs on** {{ range $j, $p := . }}{{ if $j }}, {{ end }}{{ template "provider" $p }}{{ end }}
+{{- end }}
+{{ end }}{{ end }}
+{{- if .Appendix }}{{ with .FilteredOut }}
+<details>
+<summary>Filtered out ({{ len . }})</summary>
+
+{{ range . }}- {{ if .Link }}[{{ escape .Title }}]({{ .Link }}){{ else }}{{ escape .Title }}{{ end }}: {{ escape .Reason }}
+{{ end }}
+</details>
+{{ end }}{{ end }}
+{{- define "provider" }}{{ if .Link }}[{{ escape (print .Name) }}]({{ .Link }}){{ else }}{{ escape (print .Name) }}{{ end }}{{ end }}`
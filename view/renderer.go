@@ -0,0 +1,90 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Renderer turns the report into one of the output formats
+type Renderer interface {
+	Render(report Report) (string, error)
+}
+
+type rendererFunc func(report Report) (string, error)
+
+func (f rendererFunc) Render(report Report) (string, error) {
+	return f(report)
+}
+
+// NewRenderer returns the renderer for the format. The templates are only used for html.
+func NewRenderer(format string, templates Templates) (Renderer, error) {
+	switch format {
+	case FormatHtml:
+		return rendererFunc(func(r Report) (string, error) { return NewHtmlTemplate(r, templates).ExecuteHtmlTemplate() }), nil
+	case FormatJson:
+		return rendererFunc(func(r Report) (string, error) { return NewJsonTemplate(r).ExecuteJsonTemplate() }), nil
+	case FormatMarkdown:
+		return rendererFunc(func(r Report) (string, error) { return NewMarkdownTemplate(r).ExecuteMarkdownTemplate() }), nil
+	case FormatText:
+		return rendererFunc(func(r Report) (string, error) { return NewTextTemplate(r).ExecuteTextTemplate() }), nil
+	}
+	return nil, fmt.Errorf("unsupported format: %s", format)
+}
+
+// FileExtension returns the extension of the files in the format
+func FileExtension(format string) string {
+	switch format {
+	case FormatMarkdown:
+		return "md"
+	case FormatText:
+		return "txt"
+	}
+	return format
+}
+
+// premiereLine describes when and where the show premieres, e.g. "Season 2 premieres Tue Jun 8 on FX"
+func premiereLine(show tvshow.TvShow) string {
+	if show.PremiereDate.IsZero() && show.Network == "" {
+		return ""
+	}
+
+	line := "Premieres"
+	if show.Season > 1 {
+		line = fmt.Sprintf("Season %d premieres", show.Season)
+	}
+	if !show.PremiereDate.IsZero() {
+		line += " " + show.PremiereDate.Format("Mon Jan 2")
+	}
+	if show.Network != "" {
+		line += " on " + show.Network
+	}
+	return line
+}
+
+func formatNumber(num int) string {
+	p := message.NewPrinter(language.English)
+	return p.Sprintf("%d", num)
+}
+
+func filterProviders(providers []streamer.Provider, streaming bool) []streamer.Provider {
+	filtered := make([]streamer.Provider, 0)
+	for _, p := range providers {
+		if p.IsStreaming() == streaming {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+func providerNames(providers []streamer.Provider) string {
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, string(p.Name))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,153 @@
+package view
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+var testReport = Report{
+	Title:     "my-conf",
+	StartDate: "June 4",
+	EndDate:   "June 11",
+	Sections: []Section{
+		{
+			Id:    SectionReturning,
+			Title: "Returning Series",
+			Shows: []tvshow.TvShow{{
+				Title:            "Game of Thrones",
+				Link:             "https://www.imdb.com/title/tt0944947/",
+				Image:            "https://m.media-amazon.com/images/M/got.jpg",
+				Genres:           []string{"Action", "Adventure", "Drama"},
+				Rating:           tvshow.Rating{AverageRating: "9.2", RatingCount: 1865597},
+				Score:            100,
+				PersonalRating:   9,
+				StreamingOptions: []streamer.Provider{{Name: "HBO", Type: streamer.Network}},
+				PremiereDate:     time.Date(2020, 6, 9, 0, 0, 0, 0, time.UTC),
+				Network:          "HBO",
+				Season:           8,
+			}},
+		},
+		{
+			Id:    SectionNew,
+			Title: "New Series",
+			Shows: []tvshow.TvShow{{
+				Title:            "#BlackAF",
+				Link:             "https://www.imdb.com/title/tt10311562/",
+				Genres:           []string{"Comedy"},
+				Rating:           tvshow.Rating{AverageRating: "6.6", RatingCount: 1516},
+				Score:            39,
+				Description:      "A father takes an irreverent and honest approach to parenting and relationships.",
+				StreamingOptions: []streamer.Provider{{Name: streamer.Netflix, Type: streamer.Streaming, Link: "https://www.netflix.com"}},
+				IsNewSeries:      true,
+			}},
+		},
+	},
+}
+
+func Test_Render(t *testing.T) {
+	testcases := map[string]struct {
+		Format   string
+		Expected []string
+	}{
+		"Markdown": {
+			Format: FormatMarkdown,
+			Expected: []string{
+				"# Premieres from June 4 through June 11",
+				"## Returning Series",
+				"### [\\#BlackAF](https://www.imdb.com/title/tt10311562/)",
+				"**9.2**/10 (1,865,597 ratings) · Score **100**/100",
+				"You rated it 9/10",
+				"Season 8 premieres Tue Jun 9 on HBO",
+				"**Available on** [Netflix](https://www.netflix.com)",
+				"**Airs on** HBO",
+			},
+		},
+		"Text": {
+			Format: FormatText,
+			Expected: []string{
+				"Premieres from June 4 through June 11\n=====================================",
+				"NEW SERIES\n----------",
+				"  Rating:    9.2/10 (1,865,597 ratings)",
+				"  You rated: 9/10",
+				"Game of Thrones\n  Season 8 premieres Tue Jun 9 on HBO\n",
+				"  Available: Netflix",
+				"  Airs on:   HBO",
+				"  A father takes an irreverent and honest approach to parenting and\n  relationships.",
+			},
+		},
+		"Html": {
+			Format:   FormatHtml,
+			Expected: []string{"<h1>New Series</h1>", "Game of Thrones", `<div class="premiere small grey">Season 8 premieres Tue Jun 9 on HBO</div>`},
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		renderer, err := NewRenderer(testdata.Format, defaultTemplates(t))
+		require.NoError(t, err, testcase)
+
+		//when
+		out, err := renderer.Render(testReport)
+
+		//then
+		require.NoError(t, err, testcase)
+		for _, expected := range testdata.Expected {
+			assert.Contains(t, out, expected, testcase)
+		}
+	}
+}
+
+func Test_NewRenderer_Unsupported(t *testing.T) {
+	_, err := NewRenderer("pdf", defaultTemplates(t))
+	assert.Error(t, err)
+}
+
+func Test_Render_Appendix(t *testing.T) {
+	//given
+	report := testReport
+	report.Appendix = true
+	report.FilteredOut = []FilteredTvShow{
+		{Title: "Sweet Magnolias", Code: "score_too_low", Reason: "score is too low: 35", Score: 35, Link: "https://www.imdb.com/title/tt10240086/"},
+		{Title: "The Bachelor", Code: "genre_mismatch", Reason: "not an interesting genre: Reality"},
+	}
+
+	testcases := map[string]struct {
+		Format   string
+		Expected []string
+	}{
+		"Markdown": {
+			Format:   FormatMarkdown,
+			Expected: []string{"<summary>Filtered out (2)</summary>", "- [Sweet Magnolias](https://www.imdb.com/title/tt10240086/): score is too low: 35", "- The Bachelor: not an interesting genre: Reality"},
+		},
+		"Text": {
+			Format:   FormatText,
+			Expected: []string{"FILTERED OUT\n------------\n\nSweet Magnolias: score is too low: 35\nThe Bachelor: not an interesting genre: Reality"},
+		},
+		"Html": {
+			Format:   FormatHtml,
+			Expected: []string{"<summary>Filtered out (2)</summary>", `<a href="https://www.imdb.com/title/tt10240086/">Sweet Magnolias</a>`, "35/100"},
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		renderer, err := NewRenderer(testdata.Format, defaultTemplates(t))
+		require.NoError(t, err, testcase)
+
+		//when
+		out, err := renderer.Render(report)
+		hidden, hiddenErr := renderer.Render(testReport)
+
+		//then
+		require.NoError(t, err, testcase)
+		require.NoError(t, hiddenErr, testcase)
+		for _, expected := range testdata.Expected {
+			assert.Contains(t, out, expected, testcase)
+		}
+		assert.NotContains(t, hidden, "Filtered out", testcase)
+	}
+}
@@ -0,0 +1,85 @@
+package view
+
+import (
+	"time"
+
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+const (
+	FormatHtml     = "html"
+	FormatJson     = "json"
+	FormatMarkdown = "markdown"
+	FormatText     = "text"
+)
+
+// The ids of the sections which aren't groups
+const (
+	SectionWatchlist         = "watchlist"
+	SectionTopPick           = "top_pick"
+	SectionReturning         = "returning"
+	SectionNew               = "new"
+	SectionHonorableMentions = "honorable_mentions"
+	SectionUnavailable       = "unavailable" //not on our subscribed services
+)
+
+// Report is the data of a premieres report, independent of the output format
+type Report struct {
+	Title       string
+	StartDate   string
+	EndDate     string
+	Run         RunInfo
+	Sections    []Section //in the order they're shown, each show is in only one of them
+	FilteredOut []FilteredTvShow
+	Appendix    bool //whether to list the filtered out shows in the report
+}
+
+// Section is a titled group of shows in the report
+type Section struct {
+	Id    string //e.g. new, returning or genre-drama
+	Title string
+	Shows []tvshow.TvShow
+}
+
+// RunInfo describes the run which generated the report
+type RunInfo struct {
+	StartedAt        time.Time
+	FinishedAt       time.Time
+	PremieresScraped int
+}
+
+// FilteredTvShow is a premiere which was left out of the report
+type FilteredTvShow struct {
+	Title  string
+	Code   string //the kind of reason, e.g. score_too_low or genre_mismatch
+	Reason string
+	Score  int    //zero if the show wasn't found
+	Link   string //to IMDB, if the show was found
+}
+
+// WithImages returns a copy of the report with the image of each show replaced, e.g. by a local
+// thumbnail or an inline attachment
+func (r Report) WithImages(image func(show tvshow.TvShow) string) Report {
+	sections := make([]Section, 0, len(r.Sections))
+	for _, section := range r.Sections {
+		shows := make([]tvshow.TvShow, 0, len(section.Shows))
+		for _, s := range section.Shows {
+			s.Image = image(s)
+			shows = append(shows, s)
+		}
+		section.Shows = shows
+		sections = append(sections, section)
+	}
+
+	r.Sections = sections
+	return r
+}
+
+// AllShows returns the shows of all the sections
+func (r Report) AllShows() []tvshow.TvShow {
+	shows := make([]tvshow.TvShow, 0)
+	for _, s := range r.Sections {
+		shows = append(shows, s.Shows...)
+	}
+	return shows
+}
@@ -0,0 +1,171 @@
+package view
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+const (
+	ThemeLight = "light"
+	ThemeDark  = "dark"
+
+	reportTemplate = "report.html" //the entry point of the html templates
+	emailTemplate  = "email.html"  //the entry point of the email templates
+	emptyTemplate  = "empty.html"  //the email when nothing made it into the report
+)
+
+//go:embed templates/*.html
+var builtinTemplates embed.FS
+
+// Theme is the color scheme of the html report
+type Theme struct {
+	Name       string
+	Background string
+	Text       string
+	Muted      string
+	Border     string
+}
+
+var themes = map[string]Theme{
+	ThemeLight: {Name: ThemeLight, Background: "#FFFFFF", Text: "#333333", Muted: "#6B6B6B", Border: "#E3E3E3"},
+	ThemeDark:  {Name: ThemeDark, Background: "#1E1E1E", Text: "#E8E8E8", Muted: "#A0A0A0", Border: "#3A3A3A"},
+}
+
+// Templates are the parsed html templates, both for the report and the email. The built-in ones can be
+// overridden by the files in a custom directory, e.g. a card.html defining "card" replaces only the show card.
+type Templates struct {
+	html  *template.Template
+	theme Theme
+}
+
+// NewTemplates parses the templates and checks that they can render a report, so that broken
+// custom templates fail at startup rather than after all the premieres were processed
+func NewTemplates(dir string, theme string) (Templates, error) {
+	if theme == "" {
+		theme = ThemeLight
+	}
+	th, ok := themes[theme]
+	if !ok {
+		return Templates{}, fmt.Errorf("unsupported theme: %s", theme)
+	}
+
+	t, err := template.New(reportTemplate).Funcs(htmlFuncs).ParseFS(builtinTemplates, "templates/*.html")
+	if err != nil {
+		return Templates{}, err
+	}
+	if dir != "" {
+		if t, err = t.ParseGlob(filepath.Join(dir, "*.html")); err != nil {
+			return Templates{}, fmt.Errorf("error parsing templates in %s: %w", dir, err)
+		}
+	}
+
+	templates := Templates{html: t, theme: th}
+	if err := templates.validate(); err != nil {
+		return Templates{}, fmt.Errorf("invalid templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (t Templates) validate() error {
+	for _, name := range []string{reportTemplate, emailTemplate, emptyTemplate} {
+		if t.html.Lookup(name) == nil {
+			return fmt.Errorf("missing %s", name)
+		}
+		if err := t.html.ExecuteTemplate(ioutil.Discard, name, htmlData{Report: sampleReport, Theme: t.theme}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type htmlData struct {
+	Report
+	Theme Theme
+}
+
+type cardData struct {
+	Show  tvshow.TvShow
+	Theme Theme
+}
+
+type providerData struct {
+	Provider streamer.Provider
+	Theme    Theme
+}
+
+var htmlFuncs = template.FuncMap{
+	"mod": func(i, j int) bool { return i%j == 0 },
+	"streaming": func(providers []streamer.Provider) []streamer.Provider {
+		return filterProviders(providers, true)
+	},
+	"networks": func(providers []streamer.Provider) []streamer.Provider {
+		return filterProviders(providers, false)
+	},
+	"genres": func(genres []string) string {
+		return strings.Join(genres, ", ")
+	},
+	"formatNumber": formatNumber,
+	"premiere":     premiereLine,
+	"image":        imageSource,
+	"card": func(show tvshow.TvShow, theme Theme) cardData {
+		return cardData{Show: show, Theme: theme}
+	},
+	"provider": func(p streamer.Provider, theme Theme) providerData {
+		return providerData{Provider: p, Theme: theme}
+	},
+}
+
+// imageSource lets the inline attachments of the email (cid:) through, which html/template would otherwise
+// replace as unsafe URLs
+func imageSource(src string) interface{} {
+	if strings.HasPrefix(src, "cid:") {
+		return template.URL(src)
+	}
+	return src
+}
+
+// sampleReport has a show in each of the sections with all the fields set, for validating the templates
+var sampleReport = func() Report {
+	show := tvshow.TvShow{
+		Title:            "Sample Show",
+		ImdbId:           "tt0000000",
+		Link:             "https://www.imdb.com/title/tt0000000/",
+		Image:            "https://www.imdb.com/sample.jpg",
+		Genres:           []string{"Drama"},
+		Keywords:         []string{"sample"},
+		Creators:         []string{"Jane Doe"},
+		Rating:           tvshow.Rating{AverageRating: "8.0", RatingCount: 1000},
+		Description:      "A sample show.",
+		Score:            80,
+		RelevanceScore:   50,
+		StreamingOptions: []streamer.Provider{{Name: streamer.Netflix, Type: streamer.Streaming, Link: "https://www.netflix.com", Logo: "https://www.netflix.com/favicon.ico"}, {Name: "HBO", Type: streamer.Network}},
+		PremiereDate:     time.Date(2020, 6, 11, 0, 0, 0, 0, time.UTC),
+		Network:          "Netflix",
+		Season:           2,
+		PersonalRating:   8,
+	}
+	shows := []tvshow.TvShow{show, show, show}
+
+	return Report{
+		Title:     "sample",
+		StartDate: "June 4",
+		EndDate:   "June 11",
+		Sections: []Section{
+			{Id: SectionWatchlist, Title: "From your watchlist", Shows: shows},
+			{Id: SectionNew, Title: "New Series", Shows: shows},
+		},
+		FilteredOut: []FilteredTvShow{
+			{Title: "Filtered Show", Code: "score_too_low", Reason: "score is too low: 10", Score: 10, Link: "https://www.imdb.com/title/tt0000001/"},
+			{Title: "Missing Show", Code: "not_found", Reason: "no result found"},
+		},
+		Appendix: true,
+	}
+}()
@@ -0,0 +1,75 @@
+package view
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewTemplates(t *testing.T) {
+	testcases := map[string]struct {
+		Dir         string
+		Theme       string
+		ExpectedErr bool
+		Expected    []string
+		NotExpected []string
+	}{
+		"Built-in": {
+			Expected: []string{"<h1>Returning Series</h1>", `<div class="title"><a href="https://www.imdb.com/title/tt0944947/">Game of Thrones</a></div>`, "background-color: #FFFFFF"},
+		},
+		"Dark theme": {
+			Theme:    ThemeDark,
+			Expected: []string{"background-color: #1E1E1E", `bgcolor="#1E1E1E"`},
+		},
+		"Custom card": {
+			Dir:         "testdata/custom",
+			Expected:    []string{"<h1>Returning Series</h1>", `<div class="custom-card">Game of Thrones</div>`},
+			NotExpected: []string{`<div class="title">`},
+		},
+		"Unsupported theme": {
+			Theme:       "pink",
+			ExpectedErr: true,
+		},
+		"Syntax error": {
+			Dir:         "testdata/broken",
+			ExpectedErr: true,
+		},
+		"Unknown field": {
+			Dir:         "testdata/unknownfield",
+			ExpectedErr: true,
+		},
+		"Missing directory": {
+			Dir:         "testdata/missing",
+			ExpectedErr: true,
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//when
+		templates, err := NewTemplates(testdata.Dir, testdata.Theme)
+
+		//then
+		if testdata.ExpectedErr {
+			assert.Error(t, err, testcase)
+			continue
+		}
+		require.NoError(t, err, testcase)
+
+		out, err := NewHtmlTemplate(testReport, templates).ExecuteHtmlTemplate()
+		require.NoError(t, err, testcase)
+		for _, expected := range testdata.Expected {
+			assert.Contains(t, out, expected, testcase)
+		}
+		for _, notExpected := range testdata.NotExpected {
+			assert.NotContains(t, out, notExpected, testcase)
+		}
+	}
+}
+
+// defaultTemplates returns the built-in templates with the light theme
+func defaultTemplates(t *testing.T) Templates {
+	templates, err := NewTemplates("", ThemeLight)
+	require.NoError(t, err, "The built-in templates should load")
+	return templates
+}
@@ -0,0 +1,101 @@
+package view
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/ynori7/tvshows/streamer"
+)
+
+const textWidth = 80
+
+type TextTemplate struct {
+	Report
+}
+
+func NewTextTemplate(report Report) TextTemplate {
+	return TextTemplate{
+		Report: report,
+	}
+}
+
+func (t TextTemplate) ExecuteTextTemplate() (string, error) {
+	tmpl, err := template.New("text").
+		Funcs(template.FuncMap{
+			"genres":       func(genres []string) string { return strings.Join(genres, ", ") },
+			"formatNumber": formatNumber,
+			"premiere":     premiereLine,
+			"underline":    func(s string, c string) string { return strings.Repeat(c, len([]rune(s))) },
+			"upper":        strings.ToUpper,
+			"wrap":         wrap,
+			"streaming": func(providers []streamer.Provider) string {
+				return providerNames(filterProviders(providers, true))
+			},
+			"networks": func(providers []streamer.Provider) string {
+				return providerNames(filterProviders(providers, false))
+			},
+		}).
+		Parse(textTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, t); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// wrap breaks the text into indented lines no longer than the text width
+func wrap(indent int, text string) string {
+	prefix := strings.Repeat(" ", indent)
+	lines := make([]string, 0)
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(prefix)+len(line)+1+len(word) > textWidth {
+			lines = append(lines, prefix+line)
+			line = ""
+		}
+		if line == "" {
+			line = word
+		} else {
+			line += " " + word
+		}
+	}
+	if line != "" {
+		lines = append(lines, prefix+line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+const textTemplate = `{{ $heading := printf "Premieres from %s through %s" .StartDate .EndDate }}{{ $heading }}
+{{ underline $heading "=" }}
+{{ range .Sections }}
+{{ upper .Title }}
+{{ underline .Title "-" }}
+{{ range .Shows }}
+{{ .Title }}
+{{- with premiere . }}
+  {{ . }}{{ end }}
+  Rating:    {{ .Rating.AverageRating }}/10 ({{ formatNumber .Rating.RatingCount }} ratings)
+  Score:     {{ .Score }}/100{{ if .RelevanceScore }}, relevance {{ .RelevanceScore }}/100{{ end }}
+{{- if .Genres }}
+  Genres:    {{ genres .Genres }}{{ end }}
+{{- if .PersonalRating }}
+  You rated: {{ .PersonalRating }}/10{{ end }}
+{{- with streaming .StreamingOptions }}
+  Available: {{ . }}{{ end }}
+{{- with networks .StreamingOptions }}
+  Airs on:   {{ . }}{{ end }}
+  Link:      {{ .Link }}
+{{ if .Description }}
+{{ wrap 2 .Description }}
+{{ end }}{{ end }}{{ end }}
+{{- if .Appendix }}{{ with .FilteredOut }}
+FILTERED OUT
+------------
+{{ range . }}
+{{ .Title }}: {{ .Reason }}{{ end }}
+{{ end }}{{ end }}`
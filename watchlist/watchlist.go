@@ -0,0 +1,103 @@
+package watchlist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ynori7/tvshows/imdbexport"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+// Watchlist is the set of shows which should always be reported, regardless of their score
+type Watchlist struct {
+	ids    map[string]bool
+	titles map[string]bool
+}
+
+func New() Watchlist {
+	return Watchlist{
+		ids:    make(map[string]bool),
+		titles: make(map[string]bool),
+	}
+}
+
+// Load builds a watchlist from the given files. Files ending in .csv are treated as IMDB
+// watchlist/ratings exports, anything else as a plain text list with one entry per line.
+func Load(files []string) (Watchlist, error) {
+	w := New()
+
+	for _, file := range files {
+		if strings.ToLower(filepath.Ext(file)) == ".csv" {
+			entries, err := imdbexport.ReadFile(file)
+			if err != nil {
+				return w, err
+			}
+			for _, e := range entries {
+				w.Add(e.ImdbId, e.Title)
+			}
+			continue
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return w, err
+		}
+		err = w.readTextList(f)
+		f.Close()
+		if err != nil {
+			return w, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	return w, nil
+}
+
+// readTextList reads a plain text list. Each line is either an IMDB ID, an IMDB url or a title.
+// Empty lines and lines starting with # are ignored.
+func (w Watchlist) readTextList(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if id := tvshow.ParseImdbId(line); id != "" {
+			w.Add(id, "")
+		} else {
+			w.Add("", line)
+		}
+	}
+	return scanner.Err()
+}
+
+// Add puts a show on the watchlist. The title is only used for the entries without an IMDB ID, since
+// different shows can have the same title.
+func (w Watchlist) Add(imdbId string, title string) {
+	if imdbId != "" {
+		w.ids[imdbId] = true
+	} else if title != "" {
+		w.titles[normalizeTitle(title)] = true
+	}
+}
+
+// Contains checks whether the show is on the watchlist by its IMDB ID, or by its title for the entries
+// which have no ID
+func (w Watchlist) Contains(show tvshow.TvShow) bool {
+	if show.ImdbId != "" && w.ids[show.ImdbId] {
+		return true
+	}
+	return show.Title != "" && w.titles[normalizeTitle(show.Title)]
+}
+
+func (w Watchlist) Len() int {
+	return len(w.ids) + len(w.titles)
+}
+
+func normalizeTitle(t string) string {
+	return strings.ToLower(strings.TrimSpace(t))
+}
@@ -0,0 +1,59 @@
+package watchlist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/tvshow"
+)
+
+func Test_Load(t *testing.T) {
+	//when
+	w, err := Load([]string{"testdata/watchlist.csv", "testdata/watchlist.txt"})
+
+	//then
+	require.NoError(t, err, "There was an error loading the watchlist")
+
+	testcases := map[string]struct {
+		Show     tvshow.TvShow
+		Expected bool
+	}{
+		"ID from the csv export": {
+			Show:     tvshow.TvShow{ImdbId: "tt0944947", Title: "Game of Thrones"},
+			Expected: true,
+		},
+		"ID from the text list": {
+			Show:     tvshow.TvShow{ImdbId: "tt7134908", Title: "Elite"},
+			Expected: true,
+		},
+		"URL from the text list": {
+			Show:     tvshow.TvShow{ImdbId: "tt9466298"},
+			Expected: true,
+		},
+		"Title from the text list": {
+			Show:     tvshow.TvShow{ImdbId: "tt10240086", Title: "Sweet magnolias"},
+			Expected: true,
+		},
+		"Same title as a csv entry with another ID": {
+			Show:     tvshow.TvShow{ImdbId: "tt11198330", Title: "Game of Thrones"},
+			Expected: false,
+		},
+		"Not on the list": {
+			Show:     tvshow.TvShow{ImdbId: "tt10311562", Title: "#BlackAF"},
+			Expected: false,
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		assert.Equal(t, testdata.Expected, w.Contains(testdata.Show), testcase)
+	}
+}
+
+func Test_Load_MissingFile(t *testing.T) {
+	//when
+	_, err := Load([]string{"testdata/does-not-exist.txt"})
+
+	//then
+	assert.Error(t, err)
+}
@@ -0,0 +1,222 @@
+package web
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ynori7/tvshows/archive"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+//go:embed templates/*.html
+var pageFiles embed.FS
+
+// Server is the web UI for browsing and searching the reports in the output directory
+type Server struct {
+	archive    archive.Archive
+	outputPath string
+	title      string
+	templates  view.Templates //to render the archived reports
+	pages      *template.Template
+	logger     log.FieldLogger
+}
+
+// week is a row on the index page
+type week struct {
+	Week   archive.Week
+	Report *view.Report //nil if it isn't archived
+	Link   string
+}
+
+type pageData struct {
+	PageTitle string
+	Title     string
+	Query     archive.Query
+	Weeks     []week
+	Matches   []archive.Match
+}
+
+var pageFuncs = template.FuncMap{
+	"join": strings.Join,
+	"streamers": func(show tvshow.TvShow) string {
+		names := make([]string, 0, len(show.StreamingOptions)+1)
+		for _, p := range show.StreamingOptions {
+			names = append(names, string(p.Name))
+		}
+		if len(names) == 0 && show.Network != "" {
+			names = append(names, show.Network)
+		}
+		return strings.Join(names, ", ")
+	},
+}
+
+// NewServer creates the web UI for the output directory. The title is the one from the config, if any.
+func NewServer(outputPath string, title string, templates view.Templates, logger log.FieldLogger) (*Server, error) {
+	pages, err := template.New("pages").Funcs(pageFuncs).ParseFS(pageFiles, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		archive:    archive.NewArchive(outputPath),
+		outputPath: outputPath,
+		title:      title,
+		templates:  templates,
+		pages:      pages,
+		logger:     logger,
+	}, nil
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.index)
+	mux.HandleFunc("GET /reports/{key}", s.report)
+	mux.HandleFunc("GET /search", s.search)
+	mux.Handle("GET /files/", http.StripPrefix("/files/", http.FileServer(filesOnly{http.Dir(s.outputPath)})))
+	return mux
+}
+
+// index lists the past weeks, newest first
+func (s *Server) index(w http.ResponseWriter, req *http.Request) {
+	weeks, err := s.archive.Weeks()
+	if err != nil {
+		s.error(w, err, "Error listing the reports")
+		return
+	}
+
+	data := s.pageData(archive.Query{})
+	data.Weeks = make([]week, 0, len(weeks))
+	for _, w := range weeks {
+		row := week{Week: w}
+		if w.Archived {
+			row.Link = "/reports/" + w.Key
+			if report, err := s.archive.Load(w); err == nil {
+				row.Report = &report
+			} else {
+				s.logger.WithFields(log.Fields{"Logger": "index", "week": w.Key, "error": err}).Warn("Error loading archived report")
+			}
+		} else if f, ok := w.Files["html"]; ok {
+			row.Link = "/files/" + f
+		} else {
+			for _, f := range w.Files {
+				row.Link = "/files/" + f
+				break
+			}
+		}
+		data.Weeks = append(data.Weeks, row)
+	}
+
+	s.render(w, "index.html", data)
+}
+
+// report renders an archived report with the current templates, or shows the saved one if it isn't archived
+func (s *Server) report(w http.ResponseWriter, req *http.Request) {
+	wk, err := s.archive.Week(req.PathValue("key"))
+	if err != nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	if !wk.Archived {
+		if f, ok := wk.Files["html"]; ok {
+			http.Redirect(w, req, "/files/"+path.Base(f), http.StatusFound)
+			return
+		}
+		http.NotFound(w, req)
+		return
+	}
+
+	report, err := s.archive.Load(wk)
+	if err != nil {
+		s.error(w, err, "Error loading the report")
+		return
+	}
+	report.Appendix = true //it's collapsed anyway
+
+	out, err := view.NewHtmlTemplate(report, s.templates).ExecuteHtmlTemplate()
+	if err != nil {
+		s.error(w, err, "Error rendering the report")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(out))
+}
+
+// search finds the shows in all of the archived reports by title, genre or streamer
+func (s *Server) search(w http.ResponseWriter, req *http.Request) {
+	q := archive.Query{
+		Title:    req.URL.Query().Get("title"),
+		Genre:    req.URL.Query().Get("genre"),
+		Streamer: req.URL.Query().Get("streamer"),
+	}
+	if q.IsEmpty() {
+		http.Redirect(w, req, "/", http.StatusFound)
+		return
+	}
+
+	matches, err := s.archive.Search(q)
+	if err != nil {
+		s.error(w, err, "Error searching the reports")
+		return
+	}
+
+	data := s.pageData(q)
+	data.Matches = matches
+	s.render(w, "search.html", data)
+}
+
+func (s *Server) pageData(q archive.Query) pageData {
+	pageTitle := s.title
+	if pageTitle == "" {
+		pageTitle = "TV Shows"
+	}
+	return pageData{PageTitle: pageTitle, Title: s.title, Query: q}
+}
+
+func (s *Server) render(w http.ResponseWriter, name string, data pageData) {
+	var out bytes.Buffer
+	if err := s.pages.ExecuteTemplate(&out, name, data); err != nil {
+		s.error(w, err, "Error rendering the page")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(out.Bytes())
+}
+
+func (s *Server) error(w http.ResponseWriter, err error, msg string) {
+	s.logger.WithFields(log.Fields{"Logger": "Server", "error": err}).Error(msg)
+	status := http.StatusInternalServerError
+	if os.IsNotExist(err) {
+		status = http.StatusNotFound
+	}
+	http.Error(w, msg, status)
+}
+
+// filesOnly serves the saved files, but not the listings of the directories
+type filesOnly struct {
+	fs http.FileSystem
+}
+
+func (f filesOnly) Open(name string) (http.File, error) {
+	file, err := f.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, os.ErrNotExist
+	}
+	return file, nil
+}
@@ -0,0 +1,114 @@
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynori7/tvshows/archive"
+	"github.com/ynori7/tvshows/streamer"
+	"github.com/ynori7/tvshows/tvshow"
+	"github.com/ynori7/tvshows/view"
+)
+
+func testServer(t *testing.T) http.Handler {
+	outputPath := t.TempDir()
+	require.NoError(t, archive.NewArchive(outputPath).Save(view.Report{Title: "tv", StartDate: "June 1", EndDate: "June 8", Sections: []view.Section{
+		{Id: view.SectionReturning, Title: "Returning Series", Shows: []tvshow.TvShow{{Title: "Game of Thrones", Genres: []string{"Drama"}, Link: "https://www.imdb.com/title/tt0944947/", StreamingOptions: []streamer.Provider{{Name: "HBO Max", Type: streamer.Streaming}}}}},
+	}}, time.Date(2020, 6, 8, 14, 0, 0, 0, time.UTC)))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(outputPath, "tv-20200608.html"), []byte("<html>saved</html>"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(outputPath, "tv-20200601.html"), []byte("<html>old</html>"), 0644))
+
+	templates, err := view.NewTemplates("", view.ThemeLight)
+	require.NoError(t, err)
+	server, err := NewServer(outputPath, "tv", templates, log.StandardLogger())
+	require.NoError(t, err)
+	return server.Handler()
+}
+
+func Test_Server(t *testing.T) {
+	handler := testServer(t)
+
+	testcases := map[string]struct {
+		Url              string
+		ExpectedStatus   int
+		ExpectedContains []string
+		ExpectedLocation string
+	}{
+		"Index": {
+			Url:            "/",
+			ExpectedStatus: http.StatusOK,
+			ExpectedContains: []string{
+				`<a href="/reports/tv-20200608">Mon Jun 8, 2020</a>`,
+				"June 1 through June 8",
+				`<a href="/files/tv-20200608.html">html</a>`,
+				`<a href="/files/tv-20200601.html">Mon Jun 1, 2020</a>`,
+			},
+		},
+		"Archived report": {
+			Url:              "/reports/tv-20200608",
+			ExpectedStatus:   http.StatusOK,
+			ExpectedContains: []string{"Game of Thrones", "Returning Series"},
+		},
+		"Report which isn't archived": {
+			Url:              "/reports/tv-20200601",
+			ExpectedStatus:   http.StatusFound,
+			ExpectedLocation: "/files/tv-20200601.html",
+		},
+		"Unknown report": {
+			Url:            "/reports/tv-20200101",
+			ExpectedStatus: http.StatusNotFound,
+		},
+		"Saved file": {
+			Url:              "/files/tv-20200601.html",
+			ExpectedStatus:   http.StatusOK,
+			ExpectedContains: []string{"old"},
+		},
+		"No listing of the output": {
+			Url:            "/files/",
+			ExpectedStatus: http.StatusNotFound,
+		},
+		"No listing of the archive": {
+			Url:            "/files/archive/",
+			ExpectedStatus: http.StatusNotFound,
+		},
+		"Search": {
+			Url:              "/search?genre=drama&streamer=hbo+max",
+			ExpectedStatus:   http.StatusOK,
+			ExpectedContains: []string{"1 show found", `<a href="https://www.imdb.com/title/tt0944947/">Game of Thrones</a>`, `<a href="/reports/tv-20200608">Jun 8, 2020</a>`},
+		},
+		"Search without results": {
+			Url:              "/search?title=Sweet+Magnolias",
+			ExpectedStatus:   http.StatusOK,
+			ExpectedContains: []string{"0 shows found", `value="Sweet Magnolias"`},
+		},
+		"Empty search": {
+			Url:              "/search",
+			ExpectedStatus:   http.StatusFound,
+			ExpectedLocation: "/",
+		},
+	}
+
+	for testcase, testdata := range testcases {
+		//given
+		rec := httptest.NewRecorder()
+
+		//when
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", testdata.Url, nil))
+
+		//then
+		assert.Equal(t, testdata.ExpectedStatus, rec.Code, testcase)
+		for _, s := range testdata.ExpectedContains {
+			assert.Contains(t, rec.Body.String(), s, testcase)
+		}
+		if testdata.ExpectedLocation != "" {
+			assert.Equal(t, testdata.ExpectedLocation, rec.Header().Get("Location"), testcase)
+		}
+	}
+}